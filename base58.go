@@ -2,9 +2,9 @@
 package base58
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
 	"fmt"
+
+	"github.com/njones/base58/core"
 )
 
 type errString string
@@ -14,27 +14,48 @@ func (e errString) Error() string {
 }
 
 // ErrInvalidChecksum is returned when the checksum does not match
-const ErrInvalidChecksum = errString("the checksum is invalid")
+const ErrInvalidChecksum = core.ErrInvalidChecksum
 
 // ErrInvalidChecksumLength is returned when the length of the decoded
 // value can't contain the length of the checksum
-const ErrInvalidChecksumLength = errString("the checksum is an invalid length")
+const ErrInvalidChecksumLength = core.ErrInvalidChecksumLength
 
 // ErrUnexpectedEOF is returned when the destination byte slice is
-// not big enough to fit all of the source decoded data
-const ErrUnexpectedEOF = errString("unexpected dst EOF")
+// not big enough to fit all of the source decoded data. Decode returns
+// a ShortBufferError, which matches ErrUnexpectedEOF with errors.Is.
+const ErrUnexpectedEOF = core.ErrUnexpectedEOF
+
+// ShortBufferError is returned by Decode when dst is too small to fit
+// the decoded data, Need is the length dst must have. It's returned
+// before anything is written, so dst is untouched.
+type ShortBufferError = core.ShortBufferError
 
 // ErrZeroLength is returned when a the src string is of length 0
-const ErrZeroLength = errString("zero length src string")
+const ErrZeroLength = core.ErrZeroLength
+
+// ErrInputTooLarge is returned when decoding input that is over the
+// limits set with WithMaxEncodedLen or WithMaxDecodedLen
+const ErrInputTooLarge = core.ErrInputTooLarge
+
+// ErrInvalidVersion is returned when the decoded data doesn't start
+// with the version prefix of the encoding
+const ErrInvalidVersion = core.ErrInvalidVersion
+
+// InvalidDigitError is returned by Decode for a byte that isn't a
+// digit of the encoding alphabet
+type InvalidDigitError = core.InvalidDigitError
+
+// ErrInvalidAlphabet is returned by NewEncodingE and NewRadixEncodingE
+// when the alphabet can't be used by an Encoding
+const ErrInvalidAlphabet = errString("invalid encoding alphabet")
+
+// ErrInvalidOption is returned by NewEncodingE and NewRadixEncodingE
+// when the options conflict with each other
+const ErrInvalidOption = errString("invalid encoding option")
 
 const bitcoinAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
 const flickrAlphabet = "123456789abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ"
 
-var (
-	decodeBlockSizes = [...]int{0, 0, 1, 2, 3, 3, 4, 5, 6, 6, 7, 8}
-	encodeBlockSizes = [...]int{0, 2, 3, 5, 6, 7, 9, 10, 11}
-)
-
 // StdEncoding is the standard base58 encoding based on the bitcoin alphabet
 var StdEncoding = NewEncoding(bitcoinAlphabet)
 
@@ -46,21 +67,35 @@ var FlickrEncoding = NewEncoding(flickrAlphabet)
 
 // An Encoding is a radix 58 encoding/decoding scheme, defined by a
 // 58-character alphabet. The most common encoding is the "base58"
-// check encoding for bitcoin
+// check encoding for bitcoin. Encodings for other radixes are created
+// with NewRadixEncoding.
 type Encoding struct {
-	encode    string
-	decodeMap [256]int8
+	encode string
+	radix  int
+
+	checkNum    int
+	checkFunc   func([]byte) []byte
+	checkName   string // the named checksum algorithm, see ParseSpec
+	customCheck bool
+	hmac        *hmacChecksum // the key of WithHMACChecksum
+	hmacKeys    [][]byte      // the keys of WithHMACKeys
+
+	fixedWidth bool
+	checkDigit bool
+	version    []byte
+
+	maxEncodedLen int
+	maxDecodedLen int
 
-	checkNum  int
-	checkFunc func([]byte) []byte
+	codec core.Codec // the codec for the options, see compile
 }
 
-// opts is the functional option type
-type opts func(*Encoding)
+// Option is the functional option type used to configure an Encoding
+type Option func(*Encoding)
 
 // WithChecksum adds a checksum of i length using the function
 // defined in WithChecksumFunc to the end of encoded data
-func WithChecksum(i int) func(*Encoding) {
+func WithChecksum(i int) Option {
 	return func(enc *Encoding) {
 		enc.checkNum = i
 	}
@@ -69,204 +104,370 @@ func WithChecksum(i int) func(*Encoding) {
 // WithChecksumFunc replaces the default sha256(sha256()) checksum hashing
 // with a function that takes in a byte slice and returns one, it's usually
 // a cryptographic hashing function
-func WithChecksumFunc(fn func([]byte) []byte) func(*Encoding) {
+func WithChecksumFunc(fn func([]byte) []byte) Option {
 	return func(enc *Encoding) {
 		enc.checkFunc = fn
+		enc.checkName = ""
+		enc.customCheck = true
+		enc.hmac = nil
+	}
+}
+
+// WithVersion adds the version prefix v to the start of encoded data.
+// Decoding checks and then removes the prefix, returning
+// ErrInvalidVersion if it doesn't match.
+func WithVersion(v ...byte) Option {
+	return func(enc *Encoding) {
+		enc.version = append([]byte{}, v...)
+	}
+}
+
+// WithFixedWidth left-pads encoded output with the zero digit to the
+// maximum width for the input length, so encodings of equal length
+// inputs sort in the same order as their byte values. The order is
+// byte-wise string order for alphabets in ASCII order, like bitcoin.
+func WithFixedWidth() Option {
+	return func(enc *Encoding) {
+		enc.fixedWidth = true
+	}
+}
+
+// WithCheckDigit adds a single Luhn mod N check character, so 1 in 58
+// mistakes with base58 go undetected rather than 1 in 2^32 with a 4 byte
+// checksum, for short codes people type. It catches every substituted
+// digit and every swap of adjacent digits except the zero digit with the
+// last digit of the alphabet. Decoding returns ErrInvalidChecksum when
+// the check digit doesn't match.
+func WithCheckDigit() Option {
+	return func(enc *Encoding) {
+		enc.checkDigit = true
+	}
+}
+
+// WithMaxEncodedLen limits the length of strings that can be decoded
+// to n characters, Decode returns ErrInputTooLarge for longer input
+// before doing any work. Decoding is quadratic in the input length, so
+// limit untrusted input. Zero means no limit.
+func WithMaxEncodedLen(n int) Option {
+	return func(enc *Encoding) {
+		enc.maxEncodedLen = n
+	}
+}
+
+// WithMaxDecodedLen limits decoded data to n bytes, not counting the
+// checksum and version, Decode returns ErrInputTooLarge for input that
+// decodes to more. Input that is too long to fit is rejected before
+// doing any work. Zero means no limit.
+func WithMaxDecodedLen(n int) Option {
+	return func(enc *Encoding) {
+		enc.maxDecodedLen = n
 	}
 }
 
 // NewEncoding returns a new Encoding defined by the given alphabet,
 // which must be a 58-byte string.
-func NewEncoding(encoder string, options ...opts) *Encoding {
+func NewEncoding(encoder string, options ...Option) *Encoding {
 	if len(encoder) != 58 {
 		panic("encoding alphabet is not 58-bytes long")
 	}
+	return NewRadixEncoding(encoder, options...)
+}
+
+// NewRadixEncoding returns a new Encoding defined by the given alphabet,
+// with the radix set by its length which must be between 2 and 128
+// bytes, so a 62-byte alphabet gives a base62 encoding. The first byte
+// of the alphabet is the zero digit.
+func NewRadixEncoding(encoder string, options ...Option) *Encoding {
+	if len(encoder) < 2 || len(encoder) > 128 {
+		panic("encoding alphabet is not between 2 and 128 bytes long")
+	}
 
 	e := new(Encoding)
 	e.encode = encoder
-	e.checkFunc = func(b []byte) []byte {
-		sh1, sh2 := sha256.New(), sha256.New()
-		sh1.Write(b)
-		sh2.Write(sh1.Sum(nil))
-		return sh2.Sum(nil)
-	}
-	for i := 0; i < len(e.decodeMap); i++ {
-		e.decodeMap[i] = -1
-	}
-	for i := 0; i < len(encoder); i++ {
-		e.decodeMap[encoder[i]] = int8(i)
-	}
+	e.radix = len(encoder)
+	withChecksumName("sha256d")(e)
 
 	for _, opt := range options {
 		opt(e)
 	}
+	e.compile()
 
 	return e
 }
 
-// Encode encodes src using the encoding enc, writing
-// EncodedLen(len(src)) bytes to dst.
-func (enc *Encoding) Encode(dst, src []byte) (n int) {
-	binsz := len(src)
-	var i, j, high, zcount, carry int
-
-	for zcount < binsz && src[zcount] == 0 {
-		zcount++
+// compile sets the codec of enc from its options, the alphabet length
+// has already been checked so core.New can't fail
+func (enc *Encoding) compile() {
+	var verify func(data, sum []byte) bool
+	if enc.hmac != nil {
+		enc.hmac = withHMACKeys(enc.hmac, enc.hmacKeys)
+		h := enc.hmac
+		verify = func(data, sum []byte) bool { return h.match(data, sum) >= 0 }
 	}
-
-	if zcount == len(src) {
-		copy(dst, []byte("0"))
-		return 1
+	// the named checksums return their sum by value, others are copied
+	// into one
+	var sum func([]byte) core.Sum
+	if named, ok := checksumFuncs[enc.checkName]; ok {
+		sum = named.sum
+	} else if fn := enc.checkFunc; fn != nil {
+		sum = func(b []byte) (s core.Sum) {
+			copy(s[:], fn(b))
+			return s
+		}
 	}
+	enc.codec, _ = core.New(enc.encode, core.Config{
+		ChecksumLen:   enc.checkNum,
+		ChecksumFunc:  sum,
+		Verify:        verify,
+		Version:       enc.version,
+		FixedWidth:    enc.fixedWidth,
+		CheckDigit:    enc.checkDigit,
+		MaxEncodedLen: enc.maxEncodedLen,
+		MaxDecodedLen: enc.maxDecodedLen,
+	})
+}
 
-	if enc.checkNum > 0 {
-		checkSum := enc.checkFunc(src)
-		src = append(src, checkSum[:enc.checkNum]...)
-		binsz = len(src)
+// NewEncodingE is like NewEncoding but returns an error instead of
+// panicking. It also rejects alphabets that have repeated characters or
+// characters that are not printable ASCII, and checksums that are longer
+// than the output of the checksum function.
+func NewEncodingE(encoder string, options ...Option) (*Encoding, error) {
+	if len(encoder) != 58 {
+		return nil, fmt.Errorf("%w: not 58-bytes long (%d)", ErrInvalidAlphabet, len(encoder))
 	}
+	return NewRadixEncodingE(encoder, options...)
+}
 
-	size := enc.EncodedLen(binsz-zcount) - enc.checkNum
-	var buf = make([]byte, size)
-
-	high = size - 1
-	for i = zcount; i < binsz; i++ {
-		j = size - 1
-		for carry = int(src[i]); j > high || carry != 0; j-- {
-			carry = carry + 256*int(buf[j])
-			buf[j] = byte(carry % 58)
-			carry /= 58
-		}
-		high = j
+// NewRadixEncodingE is like NewRadixEncoding but returns an error
+// instead of panicking, with the checks of NewEncodingE.
+func NewRadixEncodingE(encoder string, options ...Option) (*Encoding, error) {
+	if len(encoder) < 2 || len(encoder) > 128 {
+		return nil, fmt.Errorf("%w: not between 2 and 128 bytes long (%d)", ErrInvalidAlphabet, len(encoder))
+	}
+	if err := validateAlphabet(encoder); err != nil {
+		return nil, err
 	}
 
-	for j = 0; j < size && buf[j] == 0; j++ {
+	e := NewRadixEncoding(encoder, options...)
+	if err := e.validate(); err != nil {
+		return nil, err
 	}
+	return e, nil
+}
 
-	n = size - j + zcount
-	if zcount != 0 {
-		for i = 0; i < zcount; i++ {
-			dst[i] = '1'
+// validateAlphabet checks that every character of alphabet is unique
+// printable ASCII, so Decode can map it back
+func validateAlphabet(alphabet string) error {
+	var seen [128]int
+	for i := 0; i < len(alphabet); i++ {
+		c := alphabet[i]
+		if c <= ' ' || c > '~' {
+			return fmt.Errorf("%w: non-printable or non-ASCII character (%q) at %d", ErrInvalidAlphabet, c, i)
+		}
+		if seen[c] > 0 {
+			return fmt.Errorf("%w: character (%q) repeated at %d and %d", ErrInvalidAlphabet, c, seen[c]-1, i)
 		}
+		seen[c] = i + 1
 	}
+	return nil
+}
 
-	for i = zcount; j < size; i++ {
-		dst[i] = enc.encode[buf[j]]
-		j++
+// validate checks that the options applied to enc are consistent
+func (enc *Encoding) validate() error {
+	if enc.checkNum < 0 {
+		return fmt.Errorf("%w: negative checksum length (%d)", ErrInvalidOption, enc.checkNum)
 	}
-
-	return n
+	if enc.hmac != nil && enc.checkNum < 1 {
+		return fmt.Errorf("%w: HMAC checksum without a length", ErrInvalidOption)
+	}
+	if enc.checkNum > 0 {
+		if enc.checkFunc == nil {
+			return fmt.Errorf("%w: checksum without a checksum function", ErrInvalidOption)
+		}
+		if size := len(enc.checkFunc(nil)); enc.checkNum > size {
+			return fmt.Errorf("%w: checksum length %d is longer than the %d byte checksum function output", ErrInvalidOption, enc.checkNum, size)
+		}
+		if enc.checkNum > core.MaxChecksumLen {
+			return fmt.Errorf("%w: checksum length %d is longer than %d bytes", ErrInvalidOption, enc.checkNum, core.MaxChecksumLen)
+		}
+	}
+	return nil
 }
 
-// EncodeToString returns the base58 encoding of src.
-func (enc *Encoding) EncodeToString(src []byte) string {
-	var buf = make([]byte, enc.EncodedLen(len(src))+enc.checkNum)
-	n := enc.Encode(buf, src)
-	return string(buf[:n])
+// With returns a copy of enc with the options applied after the options
+// enc was created with, enc itself is unchanged
+func (enc *Encoding) With(options ...Option) *Encoding {
+	e := new(Encoding)
+	*e = *enc
+	for _, opt := range options {
+		opt(e)
+	}
+	e.compile()
+	return e
 }
 
-// EncodedLen returns the length in bytes of the base58 encoding
-// of an input buffer of length n.
-func (enc *Encoding) EncodedLen(n int) int {
-	return ((n / 8) * 11) + encodeBlockSizes[n%8] + enc.checkNum
-}
+// Alphabet returns the alphabet of enc, the digits in value order
+func (enc *Encoding) Alphabet() string { return enc.encode }
 
-// Decode decodes src using the encoding enc. It writes at most
-// DecodedLen(len(src)) bytes to dst and returns the number of bytes
-// written. If src contains invalid base58 data, it will return the
-// number of bytes successfully written and an error.
-func (enc *Encoding) Decode(dst, src []byte) (n int, err error) {
-	if len(src) == 0 {
-		return n, ErrZeroLength
-	}
+// Radix returns the number of digits in the alphabet of enc
+func (enc *Encoding) Radix() int { return enc.radix }
 
-	var size = len(src)
+// ZeroDigit returns the digit for zero, which encodes leading zero bytes
+func (enc *Encoding) ZeroDigit() byte { return enc.encode[0] }
 
-	var zmask uint32
-	bytesleft := size % 4
-	if bytesleft > 0 {
-		zmask = (0xffffffff << uint32(bytesleft*8))
-	}
+// ChecksumLen returns the number of checksum bytes added to the data
+func (enc *Encoding) ChecksumLen() int { return enc.checkNum }
 
-	var zcount int
-	var buf = make([]uint32, (size+3)/4)
-	for ; zcount < size && src[zcount] == '1'; zcount++ {
-	}
+// HasChecksum reports whether enc adds a checksum to the data
+func (enc *Encoding) HasChecksum() bool { return enc.checkNum > 0 }
 
-	for i := zcount; i < size; i++ {
-		if src[i]&0x80 != 0 {
-			return n, fmt.Errorf("high-bit set on invalid digit")
-		}
+// CustomChecksum reports whether the checksum function was replaced
+// with WithChecksumFunc
+func (enc *Encoding) CustomChecksum() bool { return enc.customCheck }
 
-		if enc.decodeMap[src[i]] == -1 {
-			return n, fmt.Errorf("invalid base58 digit (%q)", src[i])
-		}
+// FixedWidth reports whether enc pads output with WithFixedWidth
+func (enc *Encoding) FixedWidth() bool { return enc.fixedWidth }
 
-		c := uint32(enc.decodeMap[src[i]])
+// HasCheckDigit reports whether enc adds a check digit with
+// WithCheckDigit
+func (enc *Encoding) HasCheckDigit() bool { return enc.checkDigit }
 
-		for j := len(buf) - 1; j >= 0; j-- {
-			t := uint64(buf[j])*58 + uint64(c)
-			c = uint32((t & 0x3f00000000) >> 32)
-			buf[j] = uint32(t)
-		}
+// Version returns the version prefix set with WithVersion
+func (enc *Encoding) Version() []byte { return append([]byte{}, enc.version...) }
 
-		if c > 0 {
-			return n, fmt.Errorf("output number too big (carry to the next int32)")
-		}
+// String returns the registered name of enc, or a description of its
+// alphabet and options if it isn't registered
+func (enc *Encoding) String() string {
+	if name, ok := registeredName(enc); ok {
+		return name
+	}
 
-		if buf[0]&zmask != 0 {
-			return n, fmt.Errorf("output number too big (last int32 filled too far)")
+	s := fmt.Sprintf("base%d(%q", enc.radix, enc.encode)
+	if enc.checkNum > 0 {
+		s += fmt.Sprintf(", checksum %d", enc.checkNum)
+		if enc.customCheck {
+			s += " custom"
 		}
 	}
+	if enc.fixedWidth {
+		s += ", fixed width"
+	}
+	if enc.checkDigit {
+		s += ", check digit"
+	}
+	if len(enc.version) > 0 {
+		s += fmt.Sprintf(", version %#x", enc.version)
+	}
+	return s + ")"
+}
 
-	n = zcount
-	var mark bool
-	for j := 0; j < len(buf); j++ {
-		for k, mask := range []uint32{0x18, 0x10, 0x8, 0x0} {
-			if j == 0 && bytesleft > 0 && k < 4-bytesleft {
-				continue // skip the first bytes left over
-			}
-			if n > len(dst)-1 {
-				return n - 1, ErrUnexpectedEOF
-			}
-			dst[n] = byte(buf[j] >> mask)
-			if !mark && dst[n] == 0 {
-				continue
-			} else if !mark && dst[n] > 0 {
-				mark = true
-			}
-			n++
-		}
+// checksumProbe is hashed to compare checksum functions in Equal
+var checksumProbe = []byte("base58 checksum probe")
+
+// Equal reports whether enc and other encode and decode the same way.
+// Checksum functions can't be compared directly, so they are equal when
+// they both give the same checksum for a probe value. Input size limits
+// are not compared.
+func (enc *Encoding) Equal(other *Encoding) bool {
+	if enc == other {
+		return true
+	}
+	if enc == nil || other == nil {
+		return false
 	}
+	return enc.encode == other.encode && enc.sameValues(other)
+}
 
+// sameValues reports whether enc and other encode data to the same
+// digit values, differing only in the digits of their alphabets
+func (enc *Encoding) sameValues(other *Encoding) bool {
+	if enc.radix != other.radix || enc.checkNum != other.checkNum || enc.fixedWidth != other.fixedWidth ||
+		enc.checkDigit != other.checkDigit || string(enc.version) != string(other.version) {
+		return false
+	}
 	if enc.checkNum > 0 {
-		if n < enc.checkNum {
-			return n, ErrInvalidChecksumLength
-		}
+		a, b := enc.checkFunc(checksumProbe), other.checkFunc(checksumProbe)
+		return string(a[:enc.checkNum]) == string(b[:enc.checkNum])
+	}
+	return true
+}
+
+// Encode encodes src using the encoding enc, writing at most
+// EncodedLen(len(src)) bytes to dst. It panics if dst is too small. All
+// zero input is written as "0", unless enc adds a check digit, version
+// or fixed width.
+func (enc *Encoding) Encode(dst, src []byte) (n int) {
+	if !enc.checkDigit && !enc.fixedWidth && len(enc.version) == 0 && allZero(src) {
+		copy(dst, []byte("0"))
+		return 1
+	}
+
+	n, err := enc.codec.Encode(dst, src, make([]uint64, enc.codec.AccLen(len(src))))
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
 
-		n -= enc.checkNum
-		checkSum, dst := dst[n:], dst[:n]
-		checkChecksum := enc.checkFunc(dst)
-		if hex.EncodeToString(checkSum[:enc.checkNum]) != hex.EncodeToString(checkChecksum[:enc.checkNum]) {
-			return n, ErrInvalidChecksum
+// allZero reports whether every byte of b is zero
+func allZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
 		}
 	}
+	return true
+}
 
-	return n, nil
+// EncodeToString returns the base58 encoding of src.
+func (enc *Encoding) EncodeToString(src []byte) string {
+	// one more byte for the "0" of all zero input, see Encode
+	var buf = make([]byte, enc.EncodedLen(len(src))+1)
+	n := enc.Encode(buf, src)
+	return string(buf[:n])
+}
+
+// EncodedLen returns the length in bytes of the base58 encoding
+// of an input buffer of length n, with the version and checksum.
+func (enc *Encoding) EncodedLen(n int) int {
+	return enc.codec.EncodedLen(n)
+}
+
+// Decode decodes src using the encoding enc. It writes at most
+// DecodedLen(len(src)) bytes, plus one for each leading zero digit, to
+// dst and returns the number of bytes written. If src contains invalid
+// base58 data, or dst is too small, it returns an error before writing
+// to dst. If the checksum or version doesn't match, dst holds the
+// decoded data and the error is returned with the number of bytes
+// written.
+func (enc *Encoding) Decode(dst, src []byte) (n int, err error) {
+	return enc.codec.Decode(dst, src, make([]uint64, enc.codec.AccLen(len(src))))
 }
 
 // DecodeString returns the bytes represented by the base58 string str.
 func (enc *Encoding) DecodeString(str string) ([]byte, error) {
+	return enc.decodeString(&enc.codec, str)
+}
+
+// decodeString is DecodeString using the codec c, a copy of the codec
+// of enc with a different Verify
+func (enc *Encoding) decodeString(c *core.Codec, str string) ([]byte, error) {
+	if c.TooLarge(len(str)) {
+		return nil, ErrInputTooLarge
+	}
+
 	var zcount int
-	for ; zcount < len(str) && str[zcount] == '1'; zcount++ {
+	for ; zcount < len(str) && str[zcount] == enc.encode[0]; zcount++ {
 	}
 
-	buf := make([]byte, enc.DecodedLen(len(str)))
-	n, err := enc.Decode(buf, []byte(str))
+	buf := make([]byte, zcount+c.DecodedLen(len(str)-zcount))
+	n, err := c.Decode(buf, []byte(str), make([]uint64, c.AccLen(len(str))))
 	return buf[:n], err
 }
 
 // DecodedLen returns the maximum length in bytes of the decoded data
-// corresponding to n bytes of base58-encoded data.
+// corresponding to n bytes of base58-encoded data, not counting leading
+// zero digits.
 func (enc *Encoding) DecodedLen(n int) int {
-	return (((n / 11) * 8) + decodeBlockSizes[n%11]) + 3
+	return enc.codec.DecodedLen(n)
 }
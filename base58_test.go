@@ -1,11 +1,15 @@
 package base58
 
 import (
+	"bytes"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"math/big"
+	"strings"
 	"testing"
+	"testing/quick"
 )
 
 type testPair struct {
@@ -215,11 +219,18 @@ func TestDecodingErrorCheck(t *testing.T) {
 		t.Errorf("ErrInvalidChecksumLength:: want: %q have: %q", want2, have2)
 	}
 
-	want3 := ErrUnexpectedEOF
+	want3 := ShortBufferError{Need: 24}
 	dst3 := make([]byte, 3)
-	_, have3 := BitcoinEncoding.Decode(dst3, []byte(addr3))
-	if want3 != have3 {
-		t.Errorf("ErrUnexpectedEOF:: want: %q have: %q", want3, have3)
+	n3, have3 := BitcoinEncoding.Decode(dst3, []byte(addr3))
+	if want3 != have3 || n3 != 0 || !errors.Is(have3, ErrUnexpectedEOF) {
+		t.Errorf("ShortBufferError:: want: %q have: %q", want3, have3)
+	}
+	if !bytes.Equal(dst3, make([]byte, 3)) {
+		t.Errorf("ShortBufferError:: dst written to: %x", dst3)
+	}
+	dst3 = make([]byte, have3.(ShortBufferError).Need)
+	if n, err := BitcoinEncoding.Decode(dst3, []byte(addr3)); err != nil || string(dst3[:n]) != "\x00ABCDEFGHIJKLMNOPQRS" {
+		t.Errorf("ShortBufferError:: retry have: %q (%v)", dst3[:n], err)
 	}
 
 	want4 := fmt.Errorf("invalid base58 digit (%q)", 'l')
@@ -334,13 +345,30 @@ func BenchmarkTrivialBase58Decoding(b *testing.B) {
 }
 
 func BenchmarkFastBase58Decoding(b *testing.B) {
-	b.ReportAllocs()
+	b.Run("pairs", func(b *testing.B) {
+		b.ReportAllocs()
 
-	initTestPairs()
-	b.ResetTimer()
+		initTestPairs()
+		b.ResetTimer()
 
-	for i := 0; i < b.N; i++ {
-		BitcoinEncoding.DecodeString(testPairs[i].String)
+		for i := 0; i < b.N; i++ {
+			BitcoinEncoding.DecodeString(testPairs[i].String)
+		}
+	})
+
+	for _, size := range []int{32, 64, 256, 1024} {
+		data := make([]byte, size)
+		rand.Read(data)
+		s := StdEncoding.EncodeToString(data)
+
+		b.Run(fmt.Sprintf("%d", size), func(b *testing.B) {
+			b.ReportAllocs()
+			b.SetBytes(int64(size))
+
+			for i := 0; i < b.N; i++ {
+				StdEncoding.DecodeString(s)
+			}
+		})
 	}
 }
 
@@ -410,3 +438,407 @@ func radixDecoding(str string) ([]byte, error) {
 
 	return buf, nil
 }
+
+func TestEncodeSpareCapacity(t *testing.T) {
+	src := make([]byte, 3, 16)
+	copy(src, "abc")
+	spare := src[:16]
+
+	BitcoinEncoding.EncodeToString(src)
+	if !bytes.Equal(spare[3:], make([]byte, 13)) {
+		t.Errorf("want the spare capacity untouched have: %x", spare[3:])
+	}
+}
+
+func TestCheckDigit(t *testing.T) {
+	enc := StdEncoding.With(WithCheckDigit())
+	data := []byte("invite 42")
+
+	s := enc.EncodeToString(data)
+	if want := StdEncoding.EncodeToString(data); len(s) != len(want)+1 || s[:len(want)] != want {
+		t.Fatalf("want: %s and a check digit have: %s", want, s)
+	}
+	if b, err := enc.DecodeString(s); err != nil || !bytes.Equal(b, data) {
+		t.Errorf("want: %s have: %s (%v)", data, b, err)
+	}
+
+	// every single digit substitution
+	for i := range s {
+		for _, c := range []byte(bitcoinAlphabet) {
+			if c == s[i] {
+				continue
+			}
+			typo := s[:i] + string(c) + s[i+1:]
+			if err := enc.Validate(typo); err != ErrInvalidChecksum {
+				t.Errorf("%s want: %v have: %v", typo, ErrInvalidChecksum, err)
+			}
+		}
+	}
+
+	// every adjacent swap, except the zero and last digits
+	for _, b := range [][]byte{data, []byte("another code"), {0xff, 0x00, 0x01, 0xfe}} {
+		swaps := enc.EncodeToString(b)
+		for i := 0; i+1 < len(swaps); i++ {
+			x, y := swaps[i], swaps[i+1]
+			if x == y || x == '1' && y == 'z' || x == 'z' && y == '1' {
+				continue
+			}
+			swapped := swaps[:i] + string(y) + string(x) + swaps[i+2:]
+			if err := enc.Validate(swapped); err != ErrInvalidChecksum {
+				t.Errorf("%s want: %v have: %v", swapped, ErrInvalidChecksum, err)
+			}
+		}
+	}
+
+	// the one swap a Luhn mod N check digit misses
+	missed := "1z" + s[2:len(s)-1]
+	missed += string(enc.EncodeToString(mustDecode(t, StdEncoding, missed))[len(missed)])
+	if err := enc.Validate("z1" + missed[2:]); err != nil {
+		t.Errorf("want the zero and last digit swap undetected have: %v", err)
+	}
+
+	for _, enc := range []*Encoding{
+		BitcoinEncoding.With(WithCheckDigit()),
+		FlickrEncoding.With(WithCheckDigit(), WithFixedWidth(), WithVersion(7)),
+		NewRadixEncoding("0123456789", WithCheckDigit()),
+	} {
+		s := enc.EncodeToString(data)
+		if b, err := enc.DecodeString(s); err != nil || !bytes.Equal(b, data) {
+			t.Errorf("%s want: %s have: %s (%v)", enc, data, b, err)
+		}
+	}
+
+	flickr := FlickrEncoding.With(WithCheckDigit())
+	if have, err := Transcode(enc, flickr, s); err != nil || have != flickr.EncodeToString(data) {
+		t.Errorf("transcode want: %s have: %s (%v)", flickr.EncodeToString(data), have, err)
+	}
+	if _, err := enc.DecodeString("2"); err != ErrInvalidChecksumLength {
+		t.Errorf("want: %v have: %v", ErrInvalidChecksumLength, err)
+	}
+	if _, err := enc.DecodeString("6iit0"); err != InvalidDigitError('0') {
+		t.Errorf("want: %v have: %v", InvalidDigitError('0'), err)
+	}
+
+	// all zero input gets its check digit, not the lone "0"
+	if s := enc.EncodeToString([]byte{0, 0}); len(s) != 3 || s[:2] != "11" {
+		t.Errorf("zero want: 11 and a check digit have: %s", s)
+	}
+
+	// the check digit doesn't count against the decoded limit
+	for _, test := range []struct {
+		enc  *Encoding
+		data []byte
+	}{
+		{enc.With(WithMaxDecodedLen(4)), []byte{0xff, 0xff, 0xff, 0xff}},
+		{enc.With(WithFixedWidth(), WithMaxDecodedLen(16)), bytes.Repeat([]byte{0xff}, 16)},
+	} {
+		if b, err := test.enc.DecodeString(test.enc.EncodeToString(test.data)); err != nil || !bytes.Equal(b, test.data) {
+			t.Errorf("%s want: %x have: %x (%v)", test.enc, test.data, b, err)
+		}
+	}
+	if !enc.HasCheckDigit() || StdEncoding.HasCheckDigit() {
+		t.Errorf("want only enc to have a check digit")
+	}
+}
+
+func mustDecode(t *testing.T, enc *Encoding, s string) []byte {
+	t.Helper()
+	b, err := enc.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestDecodeDigitGroups(t *testing.T) {
+	// lengths around multiples of the 10 digits read per pass
+	for size := 1; size <= 64; size++ {
+		data := make([]byte, size)
+		rand.Read(data)
+		data[0] |= 1
+
+		s := StdEncoding.EncodeToString(data)
+		want, _ := radixDecoding(s)
+		have, err := StdEncoding.DecodeString(s)
+		if err != nil || !bytes.Equal(have, want) || !bytes.Equal(have, data) {
+			t.Errorf("%d want: %x have: %x (%v)", size, want, have, err)
+		}
+	}
+}
+
+func TestFixedWidthOrder(t *testing.T) {
+	fixed := NewEncoding(bitcoinAlphabet, WithFixedWidth())
+	fixedCheck := NewEncoding(bitcoinAlphabet, WithFixedWidth(), WithChecksum(4))
+
+	roundTrip := func(enc *Encoding, b []byte) bool {
+		s := enc.EncodeToString(b)
+		if len(s) != enc.EncodedLen(len(b)) {
+			return false
+		}
+		d, err := enc.DecodeString(s)
+		return err == nil && bytes.Equal(b, d)
+	}
+
+	order := func(a, b []byte) bool {
+		if len(a) == 0 {
+			return true // Decode doesn't accept the empty string
+		}
+		if len(a) != len(b) {
+			b = append(make([]byte, len(a)), b...)[len(b):]
+		}
+		// give the leading bytes a good chance of being zero
+		if len(a) > 0 && a[0]%2 == 0 {
+			a[0] = 0
+		}
+		if len(b) > 0 && b[0]%3 == 0 {
+			b[0] = 0
+		}
+		sa, sb := fixed.EncodeToString(a), fixed.EncodeToString(b)
+		return bytes.Compare(a, b) == strings.Compare(sa, sb) &&
+			roundTrip(fixed, a) && roundTrip(fixed, b) && roundTrip(fixedCheck, a)
+	}
+
+	if err := quick.Check(order, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+
+	for _, b := range [][]byte{{0}, {0, 0, 0}, {0, 0, 1}, {0xff, 0xff}} {
+		if !roundTrip(fixed, b) || !roundTrip(fixedCheck, b) {
+			t.Errorf("round trip failed for: %x", b)
+		}
+	}
+
+	if _, err := fixed.DecodeString("1111"); err == nil {
+		t.Errorf("want invalid width error")
+	}
+	if _, err := fixed.DecodeString("zz"); err == nil {
+		t.Errorf("want too big error")
+	}
+}
+
+func TestNewEncodingE(t *testing.T) {
+	if _, err := NewEncodingE(bitcoinAlphabet, WithChecksum(4)); err != nil {
+		t.Errorf("valid alphabet: %v", err)
+	}
+
+	short := func([]byte) []byte { return []byte{1, 2} }
+	long := func([]byte) []byte { return make([]byte, 128) }
+	for _, test := range []struct {
+		alphabet string
+		options  []Option
+		want     error
+	}{
+		{"ABC123", nil, ErrInvalidAlphabet},
+		{"1" + bitcoinAlphabet[:57], nil, ErrInvalidAlphabet},
+		{" " + bitcoinAlphabet[1:], nil, ErrInvalidAlphabet},
+		{"\xe9" + bitcoinAlphabet[1:], nil, ErrInvalidAlphabet},
+		{bitcoinAlphabet, []Option{WithChecksum(-1)}, ErrInvalidOption},
+		{bitcoinAlphabet, []Option{WithChecksum(33)}, ErrInvalidOption},
+		{bitcoinAlphabet, []Option{WithChecksum(4), WithChecksumFunc(short)}, ErrInvalidOption},
+		{bitcoinAlphabet, []Option{WithChecksum(4), WithChecksumFunc(nil)}, ErrInvalidOption},
+		{bitcoinAlphabet, []Option{WithChecksum(65), WithChecksumFunc(long)}, ErrInvalidOption},
+	} {
+		enc, err := NewEncodingE(test.alphabet, test.options...)
+		if enc != nil || !errors.Is(err, test.want) {
+			t.Errorf("%q want: %v have: %v", test.alphabet, test.want, err)
+		}
+	}
+}
+
+func TestNewRadixEncodingE(t *testing.T) {
+	if enc, err := NewRadixEncodingE("0123456789", WithCheckDigit()); err != nil || enc.Radix() != 10 {
+		t.Errorf("valid alphabet: %v", err)
+	}
+
+	for _, test := range []struct {
+		alphabet string
+		options  []Option
+		want     error
+	}{
+		{"0", nil, ErrInvalidAlphabet},
+		{strings.Repeat("x", 129), nil, ErrInvalidAlphabet},
+		{"01234567890", nil, ErrInvalidAlphabet},
+		{"01\x80", nil, ErrInvalidAlphabet},
+		{"0123456789", []Option{WithChecksum(-1)}, ErrInvalidOption},
+	} {
+		enc, err := NewRadixEncodingE(test.alphabet, test.options...)
+		if enc != nil || !errors.Is(err, test.want) {
+			t.Errorf("%q want: %v have: %v", test.alphabet, test.want, err)
+		}
+	}
+}
+
+func TestRadixEncoding(t *testing.T) {
+	const digits = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	for _, radix := range []int{2, 10, 16, 36, 58, 62} {
+		enc := NewRadixEncoding(digits[:radix])
+		fixed := NewRadixEncoding(digits[:radix], WithFixedWidth())
+
+		for j := 1; j < 48; j++ {
+			b := make([]byte, j)
+			rand.Read(b)
+			b[0] = 0 // keep a leading zero byte
+			if j > 8 {
+				b[1] = 0
+			}
+
+			want := strings.Repeat("0", j-len(bytes.TrimLeft(b, "\x00")))
+			if x := new(big.Int).SetBytes(b); x.Sign() != 0 {
+				want += strings.Map(swapCase, x.Text(radix))
+			}
+
+			have := enc.EncodeToString(b)
+			if have != want && !(want == strings.Repeat("0", j) && have == "0") {
+				t.Errorf("radix %d encode want: %s have: %s", radix, want, have)
+			}
+
+			for _, e := range []*Encoding{enc, fixed} {
+				s := e.EncodeToString(b)
+				d, err := e.DecodeString(s)
+				if err != nil {
+					t.Errorf("radix %d decode [%s]: %v", radix, s, err)
+				}
+				if !bytes.Equal(b, d) {
+					t.Errorf("radix %d decode want: %x have: %x", radix, b, d)
+				}
+			}
+		}
+
+		if have, err := enc.DecodeUint64(enc.EncodeUint64(1234567890)); err != nil || have != 1234567890 {
+			t.Errorf("radix %d uint64 want: 1234567890 have: %d (%v)", radix, have, err)
+		}
+	}
+
+	ripple := NewEncoding(rippleAlphabet)
+	if have := ripple.EncodeToString([]byte{0, 0, 1}); have != "rrp" {
+		t.Errorf("zero digit want: rrp have: %s", have)
+	}
+	if have, _ := ripple.DecodeString("rrp"); !bytes.Equal(have, []byte{0, 0, 1}) {
+		t.Errorf("zero digit want: 000001 have: %x", have)
+	}
+}
+
+// swapCase maps the digits of big.Int, which are lowercase first, to
+// the uppercase first test alphabet
+func swapCase(r rune) rune {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return r - 'a' + 'A'
+	case r >= 'A' && r <= 'Z':
+		return r - 'A' + 'a'
+	}
+	return r
+}
+
+func TestEncodingAccessors(t *testing.T) {
+	if have := BitcoinEncoding.Alphabet(); have != bitcoinAlphabet {
+		t.Errorf("alphabet want: %s have: %s", bitcoinAlphabet, have)
+	}
+	if BitcoinEncoding.Radix() != 58 || BitcoinEncoding.ZeroDigit() != '1' || BitcoinEncoding.ChecksumLen() != 4 {
+		t.Errorf("want radix 58, zero digit 1 and 4 checksum bytes")
+	}
+	if !BitcoinEncoding.HasChecksum() || StdEncoding.HasChecksum() || BitcoinEncoding.CustomChecksum() {
+		t.Errorf("want only a default checksum on BitcoinEncoding")
+	}
+
+	if have := BitcoinEncoding.String(); have != "bitcoin-check" {
+		t.Errorf("registered name want: bitcoin-check have: %s", have)
+	}
+	custom := NewRadixEncoding("0123456789", WithChecksum(2), WithChecksumFunc(func(b []byte) []byte { return b }), WithFixedWidth())
+	if want, have := `base10("0123456789", checksum 2 custom, fixed width)`, custom.String(); have != want {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if !custom.CustomChecksum() || !custom.FixedWidth() {
+		t.Errorf("want custom checksum and fixed width")
+	}
+}
+
+func TestEncodingEqual(t *testing.T) {
+	sha256d := NewEncoding(bitcoinAlphabet, WithChecksum(4))
+	cb58, _ := Lookup("cb58")
+	for _, test := range []struct {
+		a, b *Encoding
+		want bool
+	}{
+		{BitcoinEncoding, BitcoinEncoding, true},
+		{BitcoinEncoding, sha256d, true},
+		{BitcoinEncoding, StdEncoding, false},
+		{BitcoinEncoding, cb58, false},
+		{StdEncoding, FlickrEncoding, false},
+		{StdEncoding, NewEncoding(bitcoinAlphabet, WithFixedWidth()), false},
+		{StdEncoding, nil, false},
+	} {
+		if have := test.a.Equal(test.b); have != test.want {
+			t.Errorf("%v == %v want: %t have: %t", test.a, test.b, test.want, have)
+		}
+	}
+}
+
+func TestEncodingWith(t *testing.T) {
+	check := StdEncoding.With(WithChecksum(4))
+	if StdEncoding.HasChecksum() {
+		t.Errorf("With must not change the original encoding")
+	}
+	if !check.Equal(BitcoinEncoding) || check == BitcoinEncoding {
+		t.Errorf("want a new encoding equal to BitcoinEncoding")
+	}
+
+	options := []Option{WithChecksum(2), WithFixedWidth()}
+	derived := BitcoinEncoding.With(options...)
+	if derived.ChecksumLen() != 2 || !derived.FixedWidth() || derived.Alphabet() != bitcoinAlphabet {
+		t.Errorf("want options applied over the original, have: %v", derived)
+	}
+}
+
+func TestInputLimits(t *testing.T) {
+	long := StdEncoding.EncodeToString(bytes.Repeat([]byte{0xff}, 200))
+	if _, err := StdEncoding.DecodeString(long); err != nil {
+		t.Errorf("StdEncoding has no limit: %v", err)
+	}
+	if _, err := BitcoinEncoding.DecodeString(long); err == ErrInputTooLarge {
+		t.Errorf("BitcoinEncoding has no limit: %v", err)
+	}
+	address, _ := Lookup("bitcoin-check")
+	if _, err := address.DecodeString(long); err != ErrInputTooLarge {
+		t.Errorf("want: %v have: %v", ErrInputTooLarge, err)
+	}
+	if address.IsValid(long) {
+		t.Errorf("want too large input to be invalid")
+	}
+
+	limited := StdEncoding.With(WithMaxDecodedLen(4))
+	for _, test := range []struct {
+		b    []byte
+		want error
+	}{
+		{[]byte{1, 2, 3, 4}, nil},
+		{[]byte{0, 0, 0, 0}, nil},
+		{[]byte{0xff, 0xff, 0xff, 0xff}, nil},
+		{[]byte{1, 2, 3, 4, 5}, ErrInputTooLarge},
+		{[]byte{0, 0, 0, 0, 1}, ErrInputTooLarge},
+		{[]byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 1}, ErrInputTooLarge},
+	} {
+		s := StdEncoding.EncodeToString(test.b)
+		if bytes.Equal(test.b, make([]byte, len(test.b))) {
+			s = strings.Repeat("1", len(test.b))
+		}
+		if _, err := limited.DecodeString(s); err != test.want {
+			t.Errorf("%x want: %v have: %v", test.b, test.want, err)
+		}
+	}
+
+	dst := make([]byte, 8)
+	if _, err := limited.Decode(dst, []byte(long)); err != ErrInputTooLarge {
+		t.Errorf("want: %v have: %v", ErrInputTooLarge, err)
+	}
+
+	// the limits are for decoding, Encode writes what's given
+	payload := bytes.Repeat([]byte{0xff}, 100)
+	s := address.EncodeToString(payload)
+	if b, err := BitcoinEncoding.DecodeString(s); err != nil || !bytes.Equal(b, payload) {
+		t.Errorf("want: %x have: %x (%v)", payload, b, err)
+	}
+	if _, err := address.DecodeString(s); err != ErrInputTooLarge {
+		t.Errorf("want: %v have: %v", ErrInputTooLarge, err)
+	}
+}
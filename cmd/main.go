@@ -22,7 +22,8 @@ func main() {
 		input    = flag.String("i", "-", `input file (use: "-" for stdin)`)
 		output   = flag.String("o", "-", `output file (use: "-" for stdout)`)
 		decode   = flag.Bool("d", false, `decode input`)
-		check    = flag.Bool("k", false, `use sha256 check`)
+		check    = flag.Bool("k", false, `use sha256 check (same as: -n bitcoin-check)`)
+		encName  = flag.String("n", "bitcoin", "encoding name, one of: "+strings.Join(base58.Names(), ", "))
 		useError = flag.Bool("e", false, `write error to stderr`)
 	)
 
@@ -33,6 +34,17 @@ func main() {
 		os.Exit(0)
 	}
 
+	if *check {
+		*encName = "bitcoin-check"
+	}
+	enc, ok := base58.Lookup(*encName)
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown encoding: %q\n", *encName)
+		os.Exit(1)
+	}
+	// files are trusted input, so lift the address length limits
+	enc = enc.With(base58.WithMaxEncodedLen(0), base58.WithMaxDecodedLen(0))
+
 	fin, fout := os.Stdin, os.Stdout
 	if *input != "-" {
 		if fin, err = os.Open(*input); err != nil {
@@ -49,14 +61,14 @@ func main() {
 	}
 
 	// separated out for better testing
-	exitCode, err = command(fin, fout, decode, check, useError, *lnBreak)
+	exitCode, err = command(fin, fout, enc, decode, useError, *lnBreak)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "input file err: %v\n", err)
 	}
 	os.Exit(exitCode)
 }
 
-func command(fin io.Reader, fout io.Writer, decode, check, useError *bool, lnBreak int) (code int, err error) {
+func command(fin io.Reader, fout io.Writer, enc *base58.Encoding, decode, useError *bool, lnBreak int) (code int, err error) {
 	var bin, decoded []byte
 
 	if bin, err = ioutil.ReadAll(fin); err != nil {
@@ -64,19 +76,14 @@ func command(fin io.Reader, fout io.Writer, decode, check, useError *bool, lnBre
 	}
 
 	if *decode {
-		decodeString := base58.StdEncoding.DecodeString
-		if *check {
-			decodeString = base58.BitcoinEncoding.DecodeString
-		}
-
-		decoded, err = decodeString(strings.TrimSpace(string(bin)))
+		decoded, err = enc.DecodeString(strings.TrimSpace(string(bin)))
 		if err != nil && err != base58.ErrInvalidChecksum {
 			return 1, fmt.Errorf("decode input err: %v\n", err)
 		}
 
 		io.Copy(fout, bytes.NewReader(decoded))
 
-		if *check && err == base58.ErrInvalidChecksum {
+		if err == base58.ErrInvalidChecksum {
 			if *useError {
 				return 3, err
 			}
@@ -85,12 +92,7 @@ func command(fin io.Reader, fout io.Writer, decode, check, useError *bool, lnBre
 		return 0, nil
 	}
 
-	encodeToString := base58.StdEncoding.EncodeToString
-	if *check {
-		encodeToString = base58.BitcoinEncoding.EncodeToString
-	}
-
-	encoded := encodeToString(bin)
+	encoded := enc.EncodeToString(bin)
 	if lnBreak > 0 {
 		lines := (len(encoded) / lnBreak) + 1
 		for i := 0; i < lines; i++ {
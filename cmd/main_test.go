@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"github.com/njones/base58"
 )
 
 func TestSimple(t *testing.T) {
@@ -11,10 +13,10 @@ func TestSimple(t *testing.T) {
 	want := "JxF12TrwXzT5jvT\n"
 	gather := new(bytes.Buffer)
 
-	var decode, check, useError bool
+	var decode, useError bool
 	var doDecode = !decode
 
-	err, code := command(strings.NewReader(have), gather, &decode, &check, &useError, 200)
+	code, err := command(strings.NewReader(have), gather, base58.StdEncoding, &decode, &useError, 200)
 	if err != nil {
 		t.Errorf("command err: %v", err)
 	}
@@ -26,11 +28,11 @@ func TestSimple(t *testing.T) {
 	}
 
 	gather.Reset()
-	err1, code1 := command(strings.NewReader(want), gather, &doDecode, &check, &useError, 200)
+	code1, err1 := command(strings.NewReader(want), gather, base58.StdEncoding, &doDecode, &useError, 200)
 	if err1 != nil {
 		t.Errorf("command err: %v", err1)
 	}
-	if code != 0 {
+	if code1 != 0 {
 		t.Errorf("code not 0: %v", code1)
 	}
 
@@ -44,11 +46,10 @@ func TestSimpleCheck(t *testing.T) {
 	want := "32UWxgjUJd9s6KywDtjJL\n"
 	gather := new(bytes.Buffer)
 
-	var decode, check, useError bool
-	check = true
+	var decode, useError bool
 	var doDecode = !decode
 
-	err, code := command(strings.NewReader(have), gather, &decode, &check, &useError, 200)
+	code, err := command(strings.NewReader(have), gather, base58.BitcoinEncoding, &decode, &useError, 200)
 	if err != nil {
 		t.Errorf("command err: %v", err)
 	}
@@ -60,11 +61,11 @@ func TestSimpleCheck(t *testing.T) {
 	}
 
 	gather.Reset()
-	err1, code1 := command(strings.NewReader(want), gather, &doDecode, &check, &useError, 200)
+	code1, err1 := command(strings.NewReader(want), gather, base58.BitcoinEncoding, &doDecode, &useError, 200)
 	if err1 != nil {
 		t.Errorf("command err: %v", err1)
 	}
-	if code != 0 {
+	if code1 != 0 {
 		t.Errorf("code not 0: %v", code1)
 	}
 
@@ -72,3 +73,20 @@ func TestSimpleCheck(t *testing.T) {
 		t.Errorf("want: %q have: %q", gather.String(), have)
 	}
 }
+
+func TestNamedEncoding(t *testing.T) {
+	have := "Hello world"
+	want := "iXf12sRWwZs5JVs\n"
+	gather := new(bytes.Buffer)
+
+	var decode, useError bool
+	enc, _ := base58.Lookup("flickr")
+
+	code, err := command(strings.NewReader(have), gather, enc, &decode, &useError, 200)
+	if err != nil || code != 0 {
+		t.Errorf("command code: %d err: %v", code, err)
+	}
+	if gather.String() != want {
+		t.Errorf("want: %q have: %q", want, gather.String())
+	}
+}
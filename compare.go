@@ -0,0 +1,76 @@
+package base58
+
+import "sort"
+
+// Compare returns -1, 0 or +1 as the number a encodes is less than,
+// equal to or greater than the number b encodes, without decoding them.
+// Numbers that are equal compare by their count of leading zero digits,
+// fewer first, so only equal strings compare as 0. With a checksum the
+// number is the data followed by its checksum. Bytes that aren't digits
+// of the alphabet sort before every digit, and by byte value among
+// themselves.
+//
+// Compare has the signature slices.SortFunc and slices.BinarySearchFunc
+// take, so those sort and search encoded strings directly.
+func (enc *Encoding) Compare(a, b string) int {
+	za, zb := enc.zeros(a), enc.zeros(b)
+	switch {
+	case len(a)-za < len(b)-zb:
+		return -1
+	case len(a)-za > len(b)-zb:
+		return +1
+	}
+
+	for i, j := za, zb; i < len(a); i, j = i+1, j+1 {
+		da, db := enc.codec.Value(a[i]), enc.codec.Value(b[j])
+		switch {
+		case da < db:
+			return -1
+		case da > db:
+			return +1
+		case da == -1 && a[i] < b[j]:
+			return -1
+		case da == -1 && a[i] > b[j]:
+			return +1
+		}
+	}
+
+	switch {
+	case za < zb:
+		return -1
+	case za > zb:
+		return +1
+	}
+	return 0
+}
+
+// zeros returns the number of leading zero digits of s
+func (enc *Encoding) zeros(s string) (n int) {
+	for ; n < len(s) && s[n] == enc.encode[0]; n++ {
+	}
+	return n
+}
+
+// Sort sorts s in increasing order by Compare
+func (enc *Encoding) Sort(s []string) {
+	sort.Sort(StringSlice{enc, s})
+}
+
+// Search returns the index of x in s, which must be sorted by Compare,
+// and whether it's there. When it isn't, the index is where x would be
+// inserted.
+func (enc *Encoding) Search(s []string, x string) (int, bool) {
+	i := sort.Search(len(s), func(i int) bool { return enc.Compare(s[i], x) >= 0 })
+	return i, i < len(s) && s[i] == x
+}
+
+// StringSlice implements sort.Interface for encoded strings, sorting
+// them in increasing order by Compare
+type StringSlice struct {
+	Encoding *Encoding
+	Strings  []string
+}
+
+func (x StringSlice) Len() int           { return len(x.Strings) }
+func (x StringSlice) Less(i, j int) bool { return x.Encoding.Compare(x.Strings[i], x.Strings[j]) < 0 }
+func (x StringSlice) Swap(i, j int)      { x.Strings[i], x.Strings[j] = x.Strings[j], x.Strings[i] }
@@ -0,0 +1,105 @@
+package base58
+
+import (
+	"bytes"
+	"math/big"
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	r := rand.New(rand.NewSource(58))
+	randBytes := func() []byte {
+		b := make([]byte, r.Intn(12))
+		r.Read(b)
+		for i := range b[:r.Intn(len(b)+1)] {
+			b[i] = 0
+		}
+		return b
+	}
+
+	// the order of the numbers, then of the leading zero bytes
+	want := func(a, b []byte) int {
+		if c := new(big.Int).SetBytes(a).Cmp(new(big.Int).SetBytes(b)); c != 0 {
+			return c
+		}
+		za, zb := len(a)-len(bytes.TrimLeft(a, "\x00")), len(b)-len(bytes.TrimLeft(b, "\x00"))
+		switch {
+		case za < zb:
+			return -1
+		case za > zb:
+			return +1
+		}
+		return 0
+	}
+
+	for i := 0; i < 5000; i++ {
+		a, b := randBytes(), randBytes()
+		if len(a) == 0 || len(b) == 0 {
+			continue
+		}
+		for _, enc := range []*Encoding{StdEncoding, FlickrEncoding} {
+			sa, sb := enc.EncodeToString(a), enc.EncodeToString(b)
+			if sa == "0" || sb == "0" {
+				continue // all zero bytes encode as "0"
+			}
+			if have := enc.Compare(sa, sb); have != want(a, b) {
+				t.Fatalf("%x %x (%s %s) want: %d have: %d", a, b, sa, sb, want(a, b), have)
+			}
+		}
+	}
+
+	// FlickrEncoding isn't in ASCII order, but sorts by digit value
+	if have := FlickrEncoding.Compare("Z", "a"); have != +1 {
+		t.Errorf("want: +1 have: %d", have)
+	}
+
+	// only equal strings compare as 0, even with bytes that aren't digits
+	for _, test := range []struct {
+		a, b string
+		want int
+	}{
+		{"2!", "2?", -1},
+		{"2?", "2!", +1},
+		{"2!", "2!", 0},
+		{"2!", "21", -1},
+		{"0", "O", -1},
+	} {
+		if have := StdEncoding.Compare(test.a, test.b); have != test.want {
+			t.Errorf("%q %q want: %d have: %d", test.a, test.b, test.want, have)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	var ids []string
+	for i := 0; i < 100; i++ {
+		ids = append(ids, FlickrEncoding.EncodeToString(big.NewInt(int64(i*7919%1000+1)).Bytes()))
+	}
+
+	sorted := append([]string{}, ids...)
+	FlickrEncoding.Sort(sorted)
+	if !sort.IsSorted(StringSlice{FlickrEncoding, sorted}) {
+		t.Fatalf("not sorted: %v", sorted)
+	}
+
+	viaSlices := append([]string{}, ids...)
+	slices.SortFunc(viaSlices, FlickrEncoding.Compare)
+	if !slices.Equal(sorted, viaSlices) {
+		t.Errorf("want: %v have: %v", sorted, viaSlices)
+	}
+
+	for _, id := range ids {
+		i, ok := FlickrEncoding.Search(sorted, id)
+		if !ok || sorted[i] != id {
+			t.Errorf("%s want found have: %d %t", id, i, ok)
+		}
+	}
+
+	missing := FlickrEncoding.EncodeToString([]byte{0x10, 0x00})
+	if i, ok := FlickrEncoding.Search(sorted, missing); ok || i != len(sorted) {
+		t.Errorf("%s want: %d false have: %d %t", missing, len(sorted), i, ok)
+	}
+}
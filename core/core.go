@@ -0,0 +1,556 @@
+// Package core is the radix codec behind package base58, without fmt or
+// other formatting packages, and without allocating. The caller supplies
+// every buffer, so it suits TinyGo and WASM builds. It imports only
+// math/bits, checksums are computed by a function of the caller that
+// returns its Sum by value.
+package core
+
+import "math/bits"
+
+type errString string
+
+func (e errString) Error() string {
+	return string(e)
+}
+
+// ErrInvalidChecksum is returned when the checksum does not match
+const ErrInvalidChecksum = errString("the checksum is invalid")
+
+// ErrInvalidChecksumLength is returned when the length of the decoded
+// value can't contain the length of the checksum
+const ErrInvalidChecksumLength = errString("the checksum is an invalid length")
+
+// ErrUnexpectedEOF is returned when the destination byte slice is
+// not big enough to fit all of the source decoded data. Decode returns
+// a ShortBufferError, which matches ErrUnexpectedEOF with errors.Is.
+const ErrUnexpectedEOF = errString("unexpected dst EOF")
+
+// ErrZeroLength is returned when a the src string is of length 0
+const ErrZeroLength = errString("zero length src string")
+
+// ErrInputTooLarge is returned when decoding input that is over the
+// MaxEncodedLen or MaxDecodedLen limits
+const ErrInputTooLarge = errString("the input is too large")
+
+// ErrInvalidVersion is returned when the decoded data doesn't start
+// with the version prefix of the codec
+const ErrInvalidVersion = errString("the version prefix is invalid")
+
+// ErrInvalidAlphabet is returned by New when the alphabet can't be used
+const ErrInvalidAlphabet = errString("invalid encoding alphabet")
+
+const (
+	errHighBit            = errString("high-bit set on invalid digit")
+	errCarryOverflow      = errString("output number too big (carry to the next int32)")
+	errWordOverflow       = errString("output number too big (last int32 filled too far)")
+	errFixedWidthLength   = errString("invalid fixed width length")
+	errFixedWidthOverflow = errString("output number too big (wider than the fixed width)")
+	errShortAcc           = errString("short acc buffer")
+)
+
+// ShortBufferError is returned when dst is too small, Need is
+// the length dst must have. It's returned before anything is written,
+// so dst is untouched.
+type ShortBufferError struct {
+	Need int
+}
+
+func (e ShortBufferError) Error() string {
+	var b [20]byte
+	i := len(b)
+	for n := e.Need; i == len(b) || n > 0; n /= 10 {
+		i--
+		b[i] = byte('0' + n%10)
+	}
+	return "short dst buffer, need " + string(b[i:]) + " bytes"
+}
+
+// Is makes a ShortBufferError match ErrUnexpectedEOF
+func (e ShortBufferError) Is(target error) bool {
+	return target == ErrUnexpectedEOF
+}
+
+// InvalidDigitError is returned by Decode for a byte that isn't a
+// digit of the alphabet
+type InvalidDigitError byte
+
+func (e InvalidDigitError) Error() string {
+	return "invalid base58 digit (" + quote(byte(e)) + ")"
+}
+
+// quote returns c as a single-quoted Go character literal, the same as
+// strconv.QuoteRune
+func quote(c byte) string {
+	const hex = "0123456789abcdef"
+	switch {
+	case c == '\'' || c == '\\':
+		return string([]byte{'\'', '\\', c, '\''})
+	case c >= ' ' && c < 0x7f:
+		return string([]byte{'\'', c, '\''})
+	case c >= 0x80 && c <= 0xa0 || c == 0xad:
+		return string([]byte{'\'', '\\', 'u', '0', '0', hex[c>>4], hex[c&0xf], '\''})
+	case c > 0xa0:
+		return string([]byte{'\'', 0xc0 | c>>6, 0x80 | c&0x3f, '\''}) // UTF-8
+	}
+	for i, esc := range []byte("\a\b\f\n\r\t\v") {
+		if c == esc {
+			return string([]byte{'\'', '\\', "abfnrtv"[i], '\''})
+		}
+	}
+	return string([]byte{'\'', '\\', 'x', hex[c>>4], hex[c&0xf], '\''})
+}
+
+// MaxChecksumLen is the longest checksum a Sum holds, the size of a
+// SHA-512 hash
+const MaxChecksumLen = 64
+
+// Sum is a checksum, returned by value so computing it doesn't allocate
+type Sum [MaxChecksumLen]byte
+
+// Config is the configuration of a Codec
+type Config struct {
+	// ChecksumLen is the number of checksum bytes added to the data, at
+	// most MaxChecksumLen
+	ChecksumLen int
+
+	// ChecksumFunc returns the checksum of the data in the first
+	// ChecksumLen bytes of a Sum. The Sum is returned by value, so a
+	// checksum that doesn't allocate, like sha256.Sum256, leaves the
+	// codec allocation free. The data is the caller's buffer and must not
+	// be kept.
+	ChecksumFunc func([]byte) Sum
+
+	// Verify, when set, replaces comparing the checksum of the data to
+	// sum when decoding, for keyed checksums that compare in constant
+	// time or accept several keys. The data and sum are the caller's
+	// buffer and must not be kept.
+	Verify func(data, sum []byte) bool
+
+	// Version is a prefix added to the data, and checked and removed
+	// when decoding
+	Version []byte
+
+	// FixedWidth left-pads the output with the zero digit to the
+	// maximum width for the input length
+	FixedWidth bool
+
+	// CheckDigit adds a Luhn mod N check character to the output, which
+	// catches every single digit mistake and most swaps of adjacent
+	// digits, without the length of a checksum
+	CheckDigit bool
+
+	// MaxEncodedLen and MaxDecodedLen limit the input Decode accepts,
+	// zero means no limit
+	MaxEncodedLen int
+	MaxDecodedLen int
+}
+
+var (
+	decodeBlockSizes = [...]int{0, 0, 1, 2, 3, 3, 4, 5, 6, 6, 7, 8}
+	encodeBlockSizes = [...]int{0, 2, 3, 5, 6, 7, 9, 10, 11}
+)
+
+// Codec encodes and decodes with an alphabet of between 2 and 128
+// digits. The zero value is not usable, use New.
+type Codec struct {
+	alphabet  string
+	decodeMap [256]int8
+
+	radix         int
+	log2Radix     uint64 // log2(radix) with 32 fraction bits, rounded down
+	digitsPerWord int    // the digits held by a uint64 word, see words.go
+
+	// radix^digitsPerWord, shifted, with its reciprocal, see divWord
+	wordDiv, wordRecip uint64
+	wordShift          uint
+
+	Config
+}
+
+// New returns the Codec for alphabet, whose length sets the radix, with
+// the first byte as the zero digit
+func New(alphabet string, config Config) (Codec, error) {
+	var c Codec
+	if len(alphabet) < 2 || len(alphabet) > 128 {
+		return c, ErrInvalidAlphabet
+	}
+
+	c.alphabet = alphabet
+	c.radix = len(alphabet)
+	c.log2Radix = log2(uint64(c.radix))
+	pow := uint64(c.radix)
+	for c.digitsPerWord = 1; pow <= ^uint64(0)/uint64(c.radix); c.digitsPerWord++ {
+		pow *= uint64(c.radix)
+	}
+	c.wordDiv, c.wordRecip, c.wordShift = reciprocal(pow)
+	for i := range c.decodeMap {
+		c.decodeMap[i] = -1
+	}
+	for i := 0; i < len(alphabet); i++ {
+		c.decodeMap[alphabet[i]] = int8(i)
+	}
+	c.Config = config
+	return c, nil
+}
+
+// log2 returns log2(x) with 32 fraction bits, rounded down, by
+// repeatedly squaring the mantissa
+func log2(x uint64) uint64 {
+	n := uint64(bits.Len64(x) - 1)
+	l := n << 32
+	m := x << (62 - n) // x/2^n in [1, 2) with 62 fraction bits
+	for i := 31; i >= 0; i-- {
+		hi, lo := bits.Mul64(m, m)
+		m = hi<<2 | lo>>62
+		if m >= 1<<63 {
+			m >>= 1
+			l |= 1 << uint(i)
+		}
+	}
+	return l
+}
+
+// Alphabet returns the digits of c in value order
+func (c *Codec) Alphabet() string { return c.alphabet }
+
+// Radix returns the number of digits in the alphabet of c
+func (c *Codec) Radix() int { return c.radix }
+
+// Value returns the value of digit, or -1 if it isn't in the alphabet
+func (c *Codec) Value(digit byte) int { return int(c.decodeMap[digit]) }
+
+// EncodedLen returns the length in bytes of the encoding of n bytes,
+// with the version and checksum
+func (c *Codec) EncodedLen(n int) int {
+	n = c.Width(n + len(c.Version) + c.ChecksumLen)
+	if c.CheckDigit {
+		n++
+	}
+	return n
+}
+
+// Width returns the maximum number of digits needed to encode n bytes,
+// this is the width of fixed width output
+func (c *Codec) Width(n int) int {
+	if c.radix == 58 {
+		return ((n / 8) * 11) + encodeBlockSizes[n%8]
+	}
+
+	// ceil(8n / log2(radix))
+	hi, lo := bits.Mul64(uint64(n)*8, 1<<32)
+	q, r := bits.Div64(hi, lo, c.log2Radix)
+	if r > 0 {
+		q++
+	}
+	return int(q)
+}
+
+// digitBytes returns n*log2(radix)/8, the bytes held by n digits,
+// rounded down
+func (c *Codec) digitBytes(n int) int {
+	hi, lo := bits.Mul64(uint64(n), c.log2Radix)
+	return int((hi<<32 | lo>>32) / 8)
+}
+
+// DecodedLen returns the maximum length in bytes of the decoded data
+// corresponding to n digits, not counting leading zero digits
+func (c *Codec) DecodedLen(n int) int {
+	if c.radix == 58 {
+		return (((n / 11) * 8) + decodeBlockSizes[n%11]) + 3
+	}
+	return c.digitBytes(n) + 3
+}
+
+// AccLen returns the number of words acc must hold to Encode n bytes
+// or to Decode n digits
+func (c *Codec) AccLen(n int) int {
+	return (n + len(c.Version) + c.ChecksumLen + 7) / 8
+}
+
+// fixedDecodedLen returns the number of bytes encoded by a fixed width
+// string of n digits, or -1 if n isn't a valid width
+func (c *Codec) fixedDecodedLen(n int) int {
+	i := c.digitBytes(n) - 1
+	if i < 0 {
+		i = 0
+	}
+	for c.Width(i) < n {
+		i++
+	}
+	if c.Width(i) != n {
+		return -1
+	}
+	return i
+}
+
+// TooLarge reports whether an input of n digits is over the limits of
+// c. Each leading zero digit decodes to one byte and other digits to a
+// number that needs at least as many bytes as the width allows, so input
+// wider than the width of the decoded limit, plus the check digit,
+// decodes to more.
+func (c *Codec) TooLarge(n int) bool {
+	if c.MaxEncodedLen > 0 && n > c.MaxEncodedLen {
+		return true
+	}
+	if c.CheckDigit {
+		n--
+	}
+	return c.MaxDecodedLen > 0 && n > c.Width(c.MaxDecodedLen+c.ChecksumLen+len(c.Version))
+}
+
+// TooLargeToEncode reports whether the encoding of n bytes is over the
+// limits of c, so Decode would reject it: more than MaxDecodedLen bytes
+// or an EncodedLen longer than MaxEncodedLen
+func (c *Codec) TooLargeToEncode(n int) bool {
+	if c.MaxDecodedLen > 0 && n > c.MaxDecodedLen {
+		return true
+	}
+	return c.MaxEncodedLen > 0 && c.EncodedLen(n) > c.MaxEncodedLen
+}
+
+// sum writes the ChecksumLen byte checksum of data to out
+func (c *Codec) sum(out, data []byte) {
+	s := c.ChecksumFunc(data)
+	copy(out, s[:c.ChecksumLen])
+}
+
+// checksumMatch reports whether sum is the checksum of data
+func (c *Codec) checksumMatch(data, sum []byte) bool {
+	if c.Verify != nil {
+		return c.Verify(data, sum)
+	}
+	s := c.ChecksumFunc(data)
+	return string(s[:len(sum)]) == string(sum)
+}
+
+// Encode writes the encoding of src to dst, which must hold
+// EncodedLen(len(src)) bytes, using acc, which must hold
+// AccLen(len(src)) words, and returns the number of bytes written.
+func (c *Codec) Encode(dst, src []byte, acc []uint64) (n int, err error) {
+	if need := c.EncodedLen(len(src)); len(dst) < need {
+		return 0, ShortBufferError{Need: need}
+	}
+	if len(acc) < c.AccLen(len(src)) {
+		return 0, errShortAcc
+	}
+
+	// dst holds at least as many bytes as the data, so the version, data
+	// and checksum are put together there before dst is used for digits
+	binsz := copy(dst, c.Version)
+	binsz += copy(dst[binsz:], src)
+	if c.ChecksumLen > 0 {
+		c.sum(dst[binsz:binsz+c.ChecksumLen], dst[:binsz])
+		binsz += c.ChecksumLen
+	}
+
+	var zcount int
+	for zcount < binsz && dst[zcount] == 0 {
+		zcount++
+	}
+
+	// load the value into big-endian words, then divide it by
+	// radix^digitsPerWord, writing the digits of each remainder to the
+	// end of the size wide digit buffer at the start of dst
+	size := c.Width(binsz - zcount)
+	words := acc[:(binsz-zcount+7)/8]
+	for i := range words {
+		words[i] = 0
+	}
+	for i := zcount; i < binsz; i++ {
+		w := len(words) - 1 - (binsz-1-i)/8
+		words[w] = words[w]<<8 | uint64(dst[i])
+	}
+
+	j := size
+	for len(words) > 0 {
+		r := divWords(words, c.wordDiv, c.wordRecip, c.wordShift)
+		for k := 0; k < c.digitsPerWord && j > 0; k++ {
+			j--
+			dst[j] = byte(r % uint64(c.radix))
+			r /= uint64(c.radix)
+		}
+		for len(words) > 0 && words[0] == 0 {
+			words = words[1:]
+		}
+	}
+	for k := 0; k < j; k++ {
+		dst[k] = 0
+	}
+
+	for j = 0; j < size && dst[j] == 0; j++ {
+	}
+
+	if c.FixedWidth {
+		zcount = c.Width(binsz) - (size - j)
+	}
+
+	n = copy(dst[zcount:], dst[j:size]) + zcount
+	for i := 0; i < zcount; i++ {
+		dst[i] = c.alphabet[0]
+	}
+	for i := zcount; i < n; i++ {
+		dst[i] = c.alphabet[dst[i]]
+	}
+
+	if c.CheckDigit {
+		dst[n] = c.alphabet[(c.radix-c.luhn(dst[:n], 2))%c.radix]
+		n++
+	}
+
+	return n, nil
+}
+
+// luhn returns the Luhn mod N sum of the digits of s, modulo the radix,
+// doubling every other digit from the last one when factor is 2, or
+// from the one before it when factor is 1. A valid string with its check
+// digit sums to 0 with a factor of 1. Digits not in the alphabet count
+// as zero, Decode rejects them later.
+func (c *Codec) luhn(s []byte, factor int) int {
+	var sum int
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(c.decodeMap[s[i]&0x7f])
+		if d < 0 || s[i]&0x80 != 0 {
+			d = 0
+		}
+		d *= factor
+		sum += d/c.radix + d%c.radix
+		factor = 3 - factor
+	}
+	return sum % c.radix
+}
+
+// Decode decodes src into dst using acc, which must hold
+// AccLen(len(src)) words, as the accumulator. It writes at most
+// DecodedLen(len(src)) bytes, plus one for each leading zero digit, to
+// dst and returns the number of bytes written. If src is invalid, or dst
+// is too small, it returns an error before writing to dst. If the
+// checksum or version doesn't match, dst holds the decoded data and the
+// error is returned with the number of bytes written.
+func (c *Codec) Decode(dst, src []byte, acc []uint64) (n int, err error) {
+	if len(src) == 0 {
+		return n, ErrZeroLength
+	}
+	if c.TooLarge(len(src)) {
+		return n, ErrInputTooLarge
+	}
+	if c.CheckDigit {
+		if len(src) < 2 {
+			return n, ErrInvalidChecksumLength
+		}
+		if last := src[len(src)-1]; last&0x80 != 0 {
+			return n, errHighBit
+		} else if c.decodeMap[last] == -1 {
+			return n, InvalidDigitError(last)
+		}
+		if c.luhn(src, 1) != 0 {
+			return n, ErrInvalidChecksum
+		}
+		src = src[:len(src)-1]
+	}
+
+	var size = len(src)
+	if c.FixedWidth && c.fixedDecodedLen(size) < 0 {
+		return n, errFixedWidthLength
+	}
+
+	var zmask uint64
+	bytesleft := size % 8
+	if bytesleft > 0 {
+		zmask = ^uint64(0) << uint(bytesleft*8)
+	}
+
+	if len(acc) < (size+7)/8 {
+		return n, errShortAcc
+	}
+
+	var zcount int
+	var buf = acc[:(size+7)/8]
+	for i := range buf {
+		buf[i] = 0
+	}
+	for ; zcount < size && src[zcount] == c.alphabet[0]; zcount++ {
+	}
+
+	// read up to digitsPerWord digits into a single word d, then multiply
+	// the accumulator by radix^m once for the m digits read
+	for i := zcount; i < size; {
+		var d, mul uint64 = 0, 1
+		for end := i + c.digitsPerWord; i < size && i < end; i++ {
+			if src[i]&0x80 != 0 {
+				return n, errHighBit
+			}
+
+			if c.decodeMap[src[i]] == -1 {
+				return n, InvalidDigitError(src[i])
+			}
+
+			d = d*uint64(c.radix) + uint64(c.decodeMap[src[i]])
+			mul *= uint64(c.radix)
+		}
+
+		if mulAddWords(buf, mul, d) > 0 {
+			return n, errCarryOverflow
+		}
+
+		if buf[0]&zmask != 0 {
+			return n, errWordOverflow
+		}
+	}
+
+	// the value is held big-endian in buf, with only the low bytesleft
+	// bytes of the first word in use, skip to the first non-zero byte
+	lead := 0
+	if bytesleft > 0 {
+		lead = 8 - bytesleft
+	}
+	for ; lead < len(buf)*8 && accByte(buf, lead) == 0; lead++ {
+	}
+
+	n = zcount
+	if c.FixedWidth {
+		n = c.fixedDecodedLen(size) - (len(buf)*8 - lead)
+		if n < 0 {
+			return 0, errFixedWidthOverflow
+		}
+	}
+
+	need := n + len(buf)*8 - lead
+	if c.MaxDecodedLen > 0 && need-c.ChecksumLen-len(c.Version) > c.MaxDecodedLen {
+		return 0, ErrInputTooLarge
+	}
+	if need > len(dst) {
+		return 0, ShortBufferError{Need: need}
+	}
+	for i := 0; i < n; i++ {
+		dst[i] = 0
+	}
+	for i := lead; i < len(buf)*8; i++ {
+		dst[n] = accByte(buf, i)
+		n++
+	}
+
+	if c.ChecksumLen > 0 {
+		if n < c.ChecksumLen {
+			return n, ErrInvalidChecksumLength
+		}
+
+		n -= c.ChecksumLen
+		if !c.checksumMatch(dst[:n], dst[n:n+c.ChecksumLen]) {
+			return n, ErrInvalidChecksum
+		}
+	}
+
+	if len(c.Version) > 0 {
+		if n < len(c.Version) || string(dst[:len(c.Version)]) != string(c.Version) {
+			return n, ErrInvalidVersion
+		}
+		n = copy(dst, dst[len(c.Version):n])
+	}
+
+	return n, nil
+}
+
+// accByte returns the i-th byte of the big-endian decoding accumulator
+func accByte(buf []uint64, i int) byte {
+	return byte(buf[i/8] >> (56 - 8*uint(i%8)))
+}
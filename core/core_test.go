@@ -0,0 +1,104 @@
+package core
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"go/build"
+	"math"
+	"strconv"
+	"testing"
+)
+
+const bitcoinAlphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+func sha256d(b []byte) (sum Sum) {
+	h := sha256.Sum256(b)
+	h = sha256.Sum256(h[:])
+	copy(sum[:], h[:])
+	return sum
+}
+
+func TestCodec(t *testing.T) {
+	c, err := New(bitcoinAlphabet, Config{ChecksumLen: 4, ChecksumFunc: sha256d})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	src, _ := hex.DecodeString("0065a16059864a2fdbc7c99a4723a8395bc6f188eb")
+	var dst [64]byte
+	var acc [8]uint64
+
+	n, err := c.Encode(dst[:], src, acc[:])
+	if have := string(dst[:n]); err != nil || have != "1AGNa15ZQXAZUgFiqJ2i7Z2DPU2J6hW62i" {
+		t.Errorf("encode have: %s (%v)", have, err)
+	}
+
+	var out [64]byte
+	m, err := c.Decode(out[:], dst[:n], acc[:])
+	if err != nil || !bytes.Equal(out[:m], src) {
+		t.Errorf("decode want: %x have: %x (%v)", src, out[:m], err)
+	}
+
+	if _, err := c.Encode(dst[:c.EncodedLen(len(src))-1], src, acc[:]); err != (ShortBufferError{Need: c.EncodedLen(len(src))}) {
+		t.Errorf("short dst have: %v", err)
+	}
+	if _, err := c.Encode(dst[:], src, acc[:1]); err != errShortAcc {
+		t.Errorf("short acc have: %v", err)
+	}
+
+	// a checksum that doesn't allocate leaves the codec allocation free
+	allocs := testing.AllocsPerRun(100, func() {
+		n, _ := c.Encode(dst[:], src, acc[:])
+		c.Decode(out[:], dst[:n], acc[:])
+	})
+	if allocs != 0 {
+		t.Errorf("want: 0 allocs have: %v", allocs)
+	}
+}
+
+func TestLengths(t *testing.T) {
+	// the lengths from the float math the codec used to do
+	for radix := 2; radix <= 128; radix++ {
+		c, _ := New(string(make([]byte, radix)), Config{})
+		digitsPerByte := 8 / math.Log2(float64(radix))
+
+		for n := 0; n < 2000; n++ {
+			if want := int(math.Ceil(float64(n) * digitsPerByte)); radix != 58 && c.Width(n) != want {
+				t.Fatalf("radix %d width %d want: %d have: %d", radix, n, want, c.Width(n))
+			}
+			if want := int(float64(n) / digitsPerByte); c.digitBytes(n) != want {
+				t.Fatalf("radix %d bytes %d want: %d have: %d", radix, n, want, c.digitBytes(n))
+			}
+		}
+	}
+}
+
+func TestErrors(t *testing.T) {
+	for i := 0; i < 256; i++ {
+		want := fmt.Sprintf("invalid base58 digit (%s)", strconv.QuoteRune(rune(i)))
+		if have := InvalidDigitError(i).Error(); have != want {
+			t.Errorf("want: %s have: %s", want, have)
+		}
+	}
+
+	for _, need := range []int{0, 7, 58, 1234567} {
+		want := fmt.Sprintf("short dst buffer, need %d bytes", need)
+		if have := (ShortBufferError{Need: need}).Error(); have != want {
+			t.Errorf("want: %s have: %s", want, have)
+		}
+	}
+}
+
+func TestImports(t *testing.T) {
+	pkg, err := build.ImportDir(".", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range pkg.Imports {
+		if path != "math/bits" {
+			t.Errorf("unwanted import: %s", path)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package core
+
+import "math/bits"
+
+// The encoder and decoder hold values as big-endian uint64 words. These
+// are the inner loops over those words, they use the assembly versions
+// on CPUs that support them.
+
+// mulAddWords sets z to z*m + c and returns the carry out of z[0]
+func mulAddWords(z []uint64, m, c uint64) uint64 {
+	if hasMULX {
+		return mulAddWordsADX(z, m, c)
+	}
+	return mulAddWordsGo(z, m, c)
+}
+
+// divWords sets z to z/d and returns the remainder, see divWordsGo
+func divWords(z []uint64, d, m uint64, s uint) uint64 {
+	if hasMULX {
+		return divWordsADX(z, d, m, s)
+	}
+	return divWordsGo(z, d, m, s)
+}
+
+// mulAddWordsGo is the pure Go mulAddWords
+func mulAddWordsGo(z []uint64, m, c uint64) uint64 {
+	for i := len(z) - 1; i >= 0; i-- {
+		hi, lo := bits.Mul64(z[i], m)
+		lo, cc := bits.Add64(lo, c, 0)
+		z[i], c = lo, hi+cc
+	}
+	return c
+}
+
+// divWordsGo sets z to z/d and returns the remainder, where d is the
+// divisor shifted left by s so that its high bit is set, and m is the
+// reciprocal of d
+func divWordsGo(z []uint64, d, m uint64, s uint) (r uint64) {
+	for i := range z {
+		z[i], r = divWord(r<<s|z[i]>>(64-s), z[i]<<s, d, m)
+		r >>= s
+	}
+	return r
+}
+
+// divWord returns the quotient and remainder of x1:x0 divided by d,
+// using the reciprocal m of d instead of a hardware divide. It is
+// algorithm 4 of Möller and Granlund, "Improved division by invariant
+// integers", the same as math/big.
+func divWord(x1, x0, d, m uint64) (q, r uint64) {
+	q, q0 := bits.Mul64(m, x1)
+	q0, c := bits.Add64(q0, x0, 0)
+	q, _ = bits.Add64(q, x1, c)
+	q++
+
+	r = x0 - q*d
+	if r > q0 {
+		q--
+		r += d
+	}
+	if r >= d {
+		q++
+		r -= d
+	}
+	return q, r
+}
+
+// reciprocal returns the divisor d shifted so that its high bit is set,
+// the shift, and the reciprocal of the shifted divisor used by divWord
+func reciprocal(d uint64) (dn, m uint64, s uint) {
+	s = uint(bits.LeadingZeros64(d))
+	dn = d << s
+	m, _ = bits.Div64(^dn, ^uint64(0), dn)
+	return dn, m, s
+}
@@ -0,0 +1,26 @@
+//go:build amd64 && !purego
+
+package core
+
+// hasMULX is true when the CPU has the BMI2 MULX and ADX ADCX
+// instructions the assembly loops use
+var hasMULX = func() bool {
+	if maxID, _, _, _ := cpuid(0, 0); maxID < 7 {
+		return false
+	}
+	_, ebx, _, _ := cpuid(7, 0)
+	return ebx&(1<<8) != 0 && ebx&(1<<19) != 0 // BMI2 and ADX
+}()
+
+func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+
+// mulAddWordsADX is mulAddWordsGo using MULX and ADCX, it keeps the
+// carry in the flags across the whole loop
+//
+//go:noescape
+func mulAddWordsADX(z []uint64, m, c uint64) uint64
+
+// divWordsADX is divWordsGo using MULX
+//
+//go:noescape
+func divWordsADX(z []uint64, d, m uint64, s uint) uint64
@@ -0,0 +1,95 @@
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// func cpuid(eaxArg, ecxArg uint32) (eax, ebx, ecx, edx uint32)
+TEXT ·cpuid(SB), NOSPLIT, $0-24
+	MOVL eaxArg+0(FP), AX
+	MOVL ecxArg+4(FP), CX
+	CPUID
+	MOVL AX, eax+8(FP)
+	MOVL BX, ebx+12(FP)
+	MOVL CX, ecx+16(FP)
+	MOVL DX, edx+20(FP)
+	RET
+
+// func mulAddWordsADX(z []uint64, m, c uint64) uint64
+TEXT ·mulAddWordsADX(SB), NOSPLIT, $0-48
+	MOVQ z_base+0(FP), DI
+	MOVQ z_len+8(FP), CX
+	MOVQ m+24(FP), DX
+	MOVQ c+32(FP), BX
+	TESTQ CX, CX
+	JZ   done
+
+	// BX holds the high word of the product one word to the right, MULX
+	// and DEC leave CF alone so the ADCX carry chain runs the whole loop
+	XORQ AX, AX
+
+loop:
+	MULXQ -8(DI)(CX*8), AX, R8
+	ADCXQ BX, AX
+	MOVQ  AX, -8(DI)(CX*8)
+	MOVQ  R8, BX
+	DECQ  CX
+	JNZ   loop
+
+	MOVQ  $0, AX
+	ADCXQ AX, BX
+
+done:
+	MOVQ BX, ret+40(FP)
+	RET
+
+// func divWordsADX(z []uint64, d, m uint64, s uint) uint64
+TEXT ·divWordsADX(SB), NOSPLIT, $0-56
+	MOVQ z_base+0(FP), DI
+	MOVQ z_len+8(FP), R9
+	MOVQ d+24(FP), R10
+	MOVQ m+32(FP), DX
+	MOVQ s+40(FP), CX
+	XORQ BX, BX
+	XORQ SI, SI
+	TESTQ R9, R9
+	JZ   done
+
+loop:
+	// BX:R12 is the remainder and z[i] shifted left by s
+	MOVQ (DI)(SI*8), AX
+	MOVQ AX, R12
+	SHLQ CX, AX, BX
+	SHLQ CX, R12
+
+	// the quotient estimate R11 = m*BX>>64 + BX + 1, R13 the low word
+	MULXQ BX, R13, R11
+	ADDQ  R12, R13
+	ADCQ  BX, R11
+	INCQ  R11
+
+	// the remainder R8 = R12 - R11*d, then the two corrections
+	MOVQ  R11, AX
+	IMULQ R10, AX
+	MOVQ  R12, R8
+	SUBQ  AX, R8
+	CMPQ  R8, R13
+	JLS   below
+	DECQ  R11
+	ADDQ  R10, R8
+
+below:
+	CMPQ R8, R10
+	JCS  next
+	INCQ R11
+	SUBQ R10, R8
+
+next:
+	MOVQ R11, (DI)(SI*8)
+	SHRQ CX, R8
+	MOVQ R8, BX
+	INCQ SI
+	CMPQ SI, R9
+	JLT  loop
+
+done:
+	MOVQ BX, ret+48(FP)
+	RET
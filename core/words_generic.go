@@ -0,0 +1,10 @@
+//go:build !amd64 || purego
+
+package core
+
+// hasMULX is false, there are no assembly loops for this architecture
+const hasMULX = false
+
+func mulAddWordsADX(z []uint64, m, c uint64) uint64 { return mulAddWordsGo(z, m, c) }
+
+func divWordsADX(z []uint64, d, m uint64, s uint) uint64 { return divWordsGo(z, d, m, s) }
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"encoding/binary"
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// randWords returns n words, mixing random values with the all zero and
+// all one words that exercise the carries
+func randWords(r *rand.Rand, n int) []uint64 {
+	z := make([]uint64, n)
+	for i := range z {
+		switch r.Intn(4) {
+		case 0:
+		case 1:
+			z[i] = ^uint64(0)
+		default:
+			z[i] = r.Uint64()
+		}
+	}
+	return z
+}
+
+// wordsInt returns the big-endian words z as a big.Int
+func wordsInt(z []uint64) *big.Int {
+	b := make([]byte, 8*len(z))
+	for i, w := range z {
+		binary.BigEndian.PutUint64(b[8*i:], w)
+	}
+	return new(big.Int).SetBytes(b)
+}
+
+func TestWords(t *testing.T) {
+	r := rand.New(rand.NewSource(58))
+	for i := 0; i < 2000; i++ {
+		n := r.Intn(20)
+		for _, radix := range []int{2, 10, 58, 128} {
+			enc, _ := New(string(make([]byte, radix)), Config{})
+			pow := new(big.Int).Exp(big.NewInt(int64(radix)), big.NewInt(int64(enc.digitsPerWord)), nil)
+
+			// z*m + c against math/big, with the assembly and Go loops
+			x, m, c := randWords(r, n), pow.Uint64(), r.Uint64()%pow.Uint64()
+			want := new(big.Int).Add(new(big.Int).Mul(wordsInt(x), pow), new(big.Int).SetUint64(c))
+
+			z1, z2 := append([]uint64{}, x...), append([]uint64{}, x...)
+			c1, c2 := mulAddWords(z1, m, c), mulAddWordsGo(z2, m, c)
+			have := wordsInt(append([]uint64{c1}, z1...))
+			if have.Cmp(want) != 0 || c1 != c2 || wordsInt(z2).Cmp(wordsInt(z1)) != 0 {
+				t.Fatalf("mul %x*%d+%d want: %x have: %x (go %x)", x, m, c, want, have, z2)
+			}
+
+			// z/d against math/big, with the assembly and Go loops
+			q, rem := new(big.Int).QuoRem(wordsInt(x), pow, new(big.Int))
+			z1, z2 = append([]uint64{}, x...), append([]uint64{}, x...)
+			r1 := divWords(z1, enc.wordDiv, enc.wordRecip, enc.wordShift)
+			r2 := divWordsGo(z2, enc.wordDiv, enc.wordRecip, enc.wordShift)
+			if wordsInt(z1).Cmp(q) != 0 || r1 != rem.Uint64() || r1 != r2 || wordsInt(z2).Cmp(q) != 0 {
+				t.Fatalf("div %x/%d want: %x %d have: %x %d (go %x %d)", x, pow, q, rem, z1, r1, z2, r2)
+			}
+		}
+	}
+}
+
+func TestDigitsPerWord(t *testing.T) {
+	for _, test := range []struct {
+		radix, digits int
+	}{
+		{2, 63}, {10, 19}, {16, 15}, {36, 12}, {58, 10}, {62, 10}, {128, 9},
+	} {
+		if c, _ := New(string(make([]byte, test.radix)), Config{}); c.digitsPerWord != test.digits {
+			t.Errorf("radix %d want: %d have: %d", test.radix, test.digits, c.digitsPerWord)
+		}
+	}
+}
+
+func BenchmarkWords(b *testing.B) {
+	z := randWords(rand.New(rand.NewSource(58)), 32)
+	enc, _ := New(string(make([]byte, 58)), Config{})
+
+	for _, bench := range []struct {
+		name string
+		fn   func()
+	}{
+		{"mulAdd", func() { mulAddWords(z, 58, 1) }},
+		{"mulAddGo", func() { mulAddWordsGo(z, 58, 1) }},
+		{"div", func() { divWords(z, enc.wordDiv, enc.wordRecip, enc.wordShift) }},
+		{"divGo", func() { divWordsGo(z, enc.wordDiv, enc.wordRecip, enc.wordShift) }},
+	} {
+		b.Run(bench.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				bench.fn()
+			}
+		})
+	}
+}
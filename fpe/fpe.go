@@ -0,0 +1,199 @@
+// Package fpe encrypts base58 strings to base58 strings of the same
+// length with FF1, the format-preserving cipher of NIST SP 800-38G, so
+// identifiers like sequential database IDs can be shown without giving
+// away their order. The numerals are the digits of an Encoding's
+// alphabet, in any radix NewRadixEncoding supports.
+package fpe
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+
+	"github.com/njones/base58"
+)
+
+type errString string
+
+func (e errString) Error() string {
+	return string(e)
+}
+
+// ErrInvalidLength is returned for strings shorter than the minimum
+// length of the radix, or longer than FF1 allows
+const ErrInvalidLength = errString("invalid length for FF1")
+
+// Cipher is an FF1 cipher over the alphabet of an Encoding
+type Cipher struct {
+	block    cipher.Block
+	alphabet string
+	radix    *big.Int
+	minLen   int
+}
+
+// New returns the FF1 Cipher using AES with key, which is 16, 24 or 32
+// bytes, over the alphabet of enc. Checksums and other options of enc
+// don't apply, only the digits.
+func New(key []byte, enc *base58.Encoding) (*Cipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("fpe: %v", err)
+	}
+
+	c := &Cipher{block: block, alphabet: enc.Alphabet(), radix: big.NewInt(int64(enc.Radix()))}
+
+	// the domain, radix^minLen, must be at least a million
+	for n := big.NewInt(1); n.Cmp(big.NewInt(1000000)) < 0; c.minLen++ {
+		n.Mul(n, c.radix)
+	}
+	return c, nil
+}
+
+// MinLen returns the length of the shortest string c encrypts, 4 for
+// base58
+func (c *Cipher) MinLen() int { return c.minLen }
+
+// Encrypt returns the encryption of s using tweak, which may be empty,
+// as a string of the same length and alphabet
+func (c *Cipher) Encrypt(s string, tweak []byte) (string, error) {
+	return c.cipher(s, tweak, true)
+}
+
+// Decrypt returns the decryption of s using tweak, see Encrypt
+func (c *Cipher) Decrypt(s string, tweak []byte) (string, error) {
+	return c.cipher(s, tweak, false)
+}
+
+// cipher is the FF1 encrypt and decrypt algorithm, the step numbers are
+// those of NIST SP 800-38G
+func (c *Cipher) cipher(s string, tweak []byte, encrypt bool) (string, error) {
+	n, t := len(s), len(tweak)
+	if n < c.minLen || uint64(n) > 1<<32-1 {
+		return "", ErrInvalidLength
+	}
+
+	x := make([]int, n)
+	for i := 0; i < n; i++ {
+		if x[i] = indexByte(c.alphabet, s[i]); x[i] < 0 {
+			return "", base58.InvalidDigitError(s[i])
+		}
+	}
+
+	// 1-4
+	u, v := n/2, n-n/2
+	a, b := c.num(x[:u]), c.num(x[u:])
+	bLen := len(new(big.Int).Sub(new(big.Int).Exp(c.radix, big.NewInt(int64(v)), nil), big.NewInt(1)).Bytes())
+	d := 4*((bLen+3)/4) + 4
+
+	// 5
+	p := []byte{1, 2, 1, 0, 0, 0, 10, byte(u)}
+	p[3], p[4], p[5] = byte(c.radix.Uint64()>>16), byte(c.radix.Uint64()>>8), byte(c.radix.Uint64())
+	p = binary.BigEndian.AppendUint32(p, uint32(n))
+	p = binary.BigEndian.AppendUint32(p, uint32(t))
+
+	modU := new(big.Int).Exp(c.radix, big.NewInt(int64(u)), nil)
+	modV := new(big.Int).Exp(c.radix, big.NewInt(int64(v)), nil)
+
+	// 6
+	q := make([]byte, t+mod(-t-bLen-1, 16)+1+bLen)
+	copy(q, tweak)
+	for j := 0; j < 10; j++ {
+		i, num := j, b
+		if !encrypt {
+			i, num = 9-j, a
+		}
+
+		// i-ii
+		q[len(q)-bLen-1] = byte(i)
+		num.FillBytes(q[len(q)-bLen:])
+		r := c.prf(append(append([]byte{}, p...), q...))
+
+		// iii-iv
+		y := new(big.Int).SetBytes(c.expand(r, d))
+
+		// v-ix
+		m := modU
+		if i%2 == 1 {
+			m = modV
+		}
+		if encrypt {
+			cc := new(big.Int).Add(a, y)
+			a, b = b, cc.Mod(cc, m)
+		} else {
+			cc := new(big.Int).Sub(b, y)
+			b, a = a, cc.Mod(cc, m)
+		}
+	}
+
+	// 7
+	out := make([]byte, n)
+	c.str(out[:u], a)
+	c.str(out[u:], b)
+	return string(out), nil
+}
+
+// prf is the CBC-MAC of data, a multiple of the block size, with AES
+func (c *Cipher) prf(data []byte) []byte {
+	y := make([]byte, aes.BlockSize)
+	for i := 0; i < len(data); i += aes.BlockSize {
+		for k := range y {
+			y[k] ^= data[i+k]
+		}
+		c.block.Encrypt(y, y)
+	}
+	return y
+}
+
+// expand returns the first d bytes of r || CIPH(r xor [1]^16) ||
+// CIPH(r xor [2]^16) ...
+func (c *Cipher) expand(r []byte, d int) []byte {
+	s := append([]byte{}, r...)
+	for j := uint64(1); len(s) < d; j++ {
+		block := append([]byte{}, r...)
+		var jb [8]byte
+		binary.BigEndian.PutUint64(jb[:], j)
+		for k := range jb {
+			block[8+k] ^= jb[k]
+		}
+		c.block.Encrypt(block, block)
+		s = append(s, block...)
+	}
+	return s[:d]
+}
+
+// num returns the number the numerals x represent, most significant
+// first
+func (c *Cipher) num(x []int) *big.Int {
+	n := new(big.Int)
+	for _, d := range x {
+		n.Mul(n, c.radix)
+		n.Add(n, big.NewInt(int64(d)))
+	}
+	return n
+}
+
+// str writes the digits of x to out, left-padded with the zero digit
+func (c *Cipher) str(out []byte, x *big.Int) {
+	x = new(big.Int).Set(x)
+	d := new(big.Int)
+	for i := len(out) - 1; i >= 0; i-- {
+		x.QuoRem(x, c.radix, d)
+		out[i] = c.alphabet[d.Int64()]
+	}
+}
+
+func indexByte(s string, c byte) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			return i
+		}
+	}
+	return -1
+}
+
+// mod returns x mod m in [0, m)
+func mod(x, m int) int {
+	return (x%m + m) % m
+}
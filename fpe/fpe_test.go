@@ -0,0 +1,93 @@
+package fpe
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/njones/base58"
+)
+
+func TestFF1(t *testing.T) {
+	const (
+		key128 = "2b7e151628aed2a6abf7158809cf4f3c"
+		key192 = key128 + "ef4359d8d580aa4f"
+		key256 = key192 + "7f036d6f04fc6a94"
+	)
+	decimal := base58.NewRadixEncoding("0123456789")
+	base36 := base58.NewRadixEncoding("0123456789abcdefghijklmnopqrstuvwxyz")
+
+	// the FF1 samples of NIST SP 800-38G
+	for _, test := range []struct {
+		key, tweak string
+		enc        *base58.Encoding
+		pt, ct     string
+	}{
+		{key128, "", decimal, "0123456789", "2433477484"},
+		{key128, "39383736353433323130", decimal, "0123456789", "6124200773"},
+		{key128, "3737373770717273373737", base36, "0123456789abcdefghi", "a9tv40mll9kdu509eum"},
+		{key192, "", decimal, "0123456789", "2830668132"},
+		{key192, "39383736353433323130", decimal, "0123456789", "2496655549"},
+		{key192, "3737373770717273373737", base36, "0123456789abcdefghi", "xbj3kv35jrawxv32ysr"},
+		{key256, "", decimal, "0123456789", "6657667009"},
+		{key256, "39383736353433323130", decimal, "0123456789", "1001623463"},
+		{key256, "3737373770717273373737", base36, "0123456789abcdefghi", "xs8a0azh2avyalyzuwd"},
+	} {
+		key, _ := hex.DecodeString(test.key)
+		tweak, _ := hex.DecodeString(test.tweak)
+		c, err := New(key, test.enc)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if have, err := c.Encrypt(test.pt, tweak); err != nil || have != test.ct {
+			t.Errorf("encrypt %s want: %s have: %s (%v)", test.pt, test.ct, have, err)
+		}
+		if have, err := c.Decrypt(test.ct, tweak); err != nil || have != test.pt {
+			t.Errorf("decrypt %s want: %s have: %s (%v)", test.ct, test.pt, have, err)
+		}
+	}
+}
+
+func TestBase58(t *testing.T) {
+	c, _ := New(make([]byte, 16), base58.FlickrEncoding)
+	if c.MinLen() != 4 {
+		t.Errorf("min length want: 4 have: %d", c.MinLen())
+	}
+
+	seen := map[string]bool{}
+	for id := uint64(1000000); id < 1000200; id++ {
+		s := base58.FlickrEncoding.EncodeUint64(id)
+		ct, err := c.Encrypt(s, []byte("users"))
+		if err != nil || len(ct) != len(s) || seen[ct] {
+			t.Fatalf("%s have: %s (%v)", s, ct, err)
+		}
+		seen[ct] = true
+
+		if _, err := base58.FlickrEncoding.DecodeString(ct); err != nil {
+			t.Errorf("%s want base58 have: %v", ct, err)
+		}
+		if pt, err := c.Decrypt(ct, []byte("users")); err != nil || pt != s {
+			t.Errorf("%s want: %s have: %s (%v)", ct, s, pt, err)
+		}
+		if other, _ := c.Encrypt(s, []byte("teams")); other == ct {
+			t.Errorf("%s want a different tweak to change %s", s, ct)
+		}
+	}
+
+	for _, test := range []struct {
+		s    string
+		want error
+	}{
+		{"abc", ErrInvalidLength},
+		{"abc0", base58.InvalidDigitError('0')},
+	} {
+		if _, err := c.Encrypt(test.s, nil); !errors.Is(err, test.want) {
+			t.Errorf("%s want: %v have: %v", test.s, test.want, err)
+		}
+	}
+
+	if _, err := New(make([]byte, 5), base58.StdEncoding); err == nil {
+		t.Errorf("want an error for a 5 byte key")
+	}
+}
@@ -0,0 +1,91 @@
+package base58
+
+import (
+	"crypto/hmac"
+	"crypto/subtle"
+	"hash"
+)
+
+// hmacChecksum is the keyed checksum set with WithHMACChecksum
+type hmacChecksum struct {
+	hash func() hash.Hash
+	keys [][]byte // the first key signs, any of them verifies
+}
+
+// sum returns the HMAC of data using key
+func (h *hmacChecksum) sum(key, data []byte) []byte {
+	mac := hmac.New(h.hash, key)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+// match returns the index of the key whose HMAC of data starts with
+// sum, or -1. Every key is tried and compared in constant time, so the
+// time taken doesn't show which key matched, or how much of sum did.
+func (h *hmacChecksum) match(data, sum []byte) int {
+	matched := -1
+	for i := len(h.keys) - 1; i >= 0; i-- {
+		if subtle.ConstantTimeCompare(h.sum(h.keys[i], data)[:len(sum)], sum) == 1 {
+			matched = i
+		}
+	}
+	return matched
+}
+
+// WithHMACChecksum adds an n byte HMAC of the data as the checksum,
+// using the hash, like sha256.New, and key. Only holders of the key can
+// make strings that decode, so they are tamper-evident tokens. Decoding
+// compares the checksum in constant time. The length n is from 1 to the
+// hash size, NewEncodingE returns ErrInvalidOption for other lengths.
+func WithHMACChecksum(hash func() hash.Hash, key []byte, n int) Option {
+	return func(enc *Encoding) {
+		h := &hmacChecksum{hash: hash, keys: [][]byte{append([]byte{}, key...)}}
+		enc.checkNum = n
+		enc.checkFunc = func(b []byte) []byte { return h.sum(h.keys[0], b) }
+		enc.checkName = ""
+		enc.customCheck = true
+		enc.hmac = h
+	}
+}
+
+// WithHMACKeys adds keys that decoding accepts along with the key of
+// WithHMACChecksum, before or after it. To rotate keys, encode with the
+// new key and keep accepting the old keys until the strings made with
+// them are no longer in use.
+func WithHMACKeys(keys ...[]byte) Option {
+	return func(enc *Encoding) {
+		old := append([][]byte{}, enc.hmacKeys...)
+		for _, key := range keys {
+			old = append(old, append([]byte{}, key...))
+		}
+		enc.hmacKeys = old
+	}
+}
+
+// withHMACKeys returns h with the keys of WithHMACKeys after its own
+// signing key, h is shared by copies of the encoding so it isn't changed
+func withHMACKeys(h *hmacChecksum, keys [][]byte) *hmacChecksum {
+	return &hmacChecksum{hash: h.hash, keys: append([][]byte{h.keys[0]}, keys...)}
+}
+
+// DecodeStringKey is DecodeString that also returns the index of the
+// key that verified str, 0 for the key of WithHMACChecksum and 1 on for
+// the keys of WithHMACKeys. The index is -1 on error, or when enc has no
+// HMAC checksum.
+func (enc *Encoding) DecodeStringKey(str string) (b []byte, key int, err error) {
+	key = -1
+	if enc.hmac == nil {
+		b, err = enc.DecodeString(str)
+		return b, key, err
+	}
+
+	c := enc.codec
+	c.Verify = func(data, sum []byte) bool {
+		key = enc.hmac.match(data, sum)
+		return key >= 0
+	}
+	if b, err = enc.decodeString(&c, str); err != nil {
+		return b, -1, err
+	}
+	return b, key, nil
+}
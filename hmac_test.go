@@ -0,0 +1,80 @@
+package base58
+
+import (
+	"crypto/sha256"
+	"errors"
+	"testing"
+)
+
+func TestHMACChecksum(t *testing.T) {
+	oldKey, newKey := []byte("old secret key"), []byte("new secret key")
+	data := []byte("user:42")
+
+	oldEnc := NewEncoding(bitcoinAlphabet, WithHMACChecksum(sha256.New, oldKey, 8))
+	newEnc := NewEncoding(bitcoinAlphabet, WithHMACChecksum(sha256.New, newKey, 8), WithHMACKeys(oldKey))
+
+	oldToken, newToken := oldEnc.EncodeToString(data), newEnc.EncodeToString(data)
+	if oldToken == newToken {
+		t.Fatalf("want different tokens for different keys have: %s", oldToken)
+	}
+	if want := len(StdEncoding.EncodeToString(append(data, make([]byte, 8)...))); len(oldToken) > want {
+		t.Errorf("want at most %d characters have: %d", want, len(oldToken))
+	}
+
+	for _, test := range []struct {
+		enc         *Encoding
+		token, want string
+		key         int
+		err         error
+	}{
+		{oldEnc, oldToken, "user:42", 0, nil},
+		{oldEnc, newToken, "", -1, ErrInvalidChecksum},
+		{newEnc, newToken, "user:42", 0, nil},
+		{newEnc, oldToken, "user:42", 1, nil},
+		{newEnc.With(WithHMACChecksum(sha256.New, []byte("other key"), 8)), newToken, "", -1, ErrInvalidChecksum},
+		{StdEncoding, "2NEpo7TZRRrLZSi2U", "Hello World!", -1, nil},
+	} {
+		b, key, err := test.enc.DecodeStringKey(test.token)
+		if key != test.key || !errors.Is(err, test.err) || err == nil && string(b) != test.want {
+			t.Errorf("%s want: %d %v have: %d %v %q", test.token, test.key, test.err, key, err, b)
+		}
+		if _, err := test.enc.DecodeString(test.token); !errors.Is(err, test.err) {
+			t.Errorf("%s DecodeString want: %v have: %v", test.token, test.err, err)
+		}
+	}
+
+	// flipping any digit breaks the HMAC
+	for i := range newToken {
+		tampered := []byte(newToken)
+		tampered[i] = bitcoinAlphabet[(StdEncoding.codec.Value(tampered[i])+1)%58]
+		if _, _, err := newEnc.DecodeStringKey(string(tampered)); err == nil {
+			t.Errorf("%s want an error for a tampered token", tampered)
+		}
+	}
+
+	if !oldEnc.HasChecksum() || !oldEnc.CustomChecksum() || oldEnc.ChecksumLen() != 8 {
+		t.Errorf("want an 8 byte custom checksum have: %s", oldEnc)
+	}
+
+	// the rotation keys can come before the signing key
+	keysFirst := NewEncoding(bitcoinAlphabet, WithHMACKeys(oldKey), WithHMACChecksum(sha256.New, newKey, 8))
+	if b, key, err := keysFirst.DecodeStringKey(oldToken); err != nil || key != 1 || string(b) != "user:42" {
+		t.Errorf("keys first want: 1 user:42 have: %d %q (%v)", key, b, err)
+	}
+	if have := keysFirst.EncodeToString(data); have != newToken {
+		t.Errorf("keys first want: %s have: %s", newToken, have)
+	}
+}
+
+func TestHMACChecksumLength(t *testing.T) {
+	for _, n := range []int{0, -1, sha256.Size + 1} {
+		if _, err := NewEncodingE(bitcoinAlphabet, WithHMACChecksum(sha256.New, []byte("key"), n)); !errors.Is(err, ErrInvalidOption) {
+			t.Errorf("%d want: %v have: %v", n, ErrInvalidOption, err)
+		}
+	}
+	for _, n := range []int{1, sha256.Size} {
+		if _, err := NewEncodingE(bitcoinAlphabet, WithHMACChecksum(sha256.New, []byte("key"), n)); err != nil {
+			t.Errorf("%d: %v", n, err)
+		}
+	}
+}
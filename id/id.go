@@ -0,0 +1,236 @@
+// Package id generates short, URL-safe identifiers rendered as fixed
+// width base58 strings.
+//
+// The time-ordered identifiers put a big-endian timestamp in their first
+// bytes, and because every identifier is padded to the same width their
+// encoded strings sort in the same order as the identifiers themselves.
+// With StdEncoding, the default, this is plain byte-wise string order as
+// the bitcoin alphabet is in ASCII order. The FlickrEncoding alphabet
+// places lowercase before uppercase, so those strings only sort by the
+// alphabet's own digit order.
+package id
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/njones/base58"
+)
+
+type errString string
+
+func (e errString) Error() string {
+	return string(e)
+}
+
+// ErrTimeRange is returned by New160 for a time before Epoch160 or more
+// than 2^32 seconds after it, in 2150, which the 32-bit timestamp can't hold
+const ErrTimeRange = errString("time is out of the ID160 timestamp range")
+
+// ID128 is a 128-bit identifier. Time-ordered values, like a ULID, hold
+// a 48-bit millisecond unix timestamp followed by 80 random bits.
+type ID128 [16]byte
+
+// ID160 is a 160-bit identifier. Time-ordered values, like a KSUID, hold
+// a 32-bit count of seconds since Epoch160 followed by 128 random bits.
+type ID160 [20]byte
+
+// Epoch160 is the start of the ID160 timestamp, the same as a KSUID
+var Epoch160 = time.Unix(1400000000, 0).UTC()
+
+const (
+	// Len128 is the encoded length of an ID128 with StdEncoding or
+	// FlickrEncoding, other encodings have the width of EncodedLen(16)
+	Len128 = 22
+
+	// Len160 is the encoded length of an ID160 with StdEncoding or
+	// FlickrEncoding, other encodings have the width of EncodedLen(20)
+	Len160 = 28
+)
+
+// Generator creates identifiers. The zero value is ready to use and reads
+// crypto/rand.Reader and time.Now.
+type Generator struct {
+	// Rand is the source of the random bits, crypto/rand.Reader when nil
+	Rand io.Reader
+
+	// Now is the clock for time-ordered identifiers, time.Now when nil
+	Now func() time.Time
+}
+
+var defaultGenerator Generator
+
+func (g *Generator) now() time.Time {
+	if g.Now == nil {
+		return time.Now()
+	}
+	return g.Now()
+}
+
+func (g *Generator) read(b []byte) error {
+	r := g.Rand
+	if r == nil {
+		r = rand.Reader
+	}
+	if _, err := io.ReadFull(r, b); err != nil {
+		return fmt.Errorf("id random read err: %v", err)
+	}
+	return nil
+}
+
+// New128 returns a time-ordered ID128
+func (g *Generator) New128() (id ID128, err error) {
+	ms := uint64(g.now().UnixNano() / int64(time.Millisecond))
+	var ts [8]byte
+	binary.BigEndian.PutUint64(ts[:], ms)
+	copy(id[:6], ts[2:])
+	return id, g.read(id[6:])
+}
+
+// New160 returns a time-ordered ID160, or ErrTimeRange if the time is
+// outside of the range of the timestamp
+func (g *Generator) New160() (id ID160, err error) {
+	sec := g.now().Unix() - Epoch160.Unix()
+	if sec < 0 || sec > 1<<32-1 {
+		return id, ErrTimeRange
+	}
+	binary.BigEndian.PutUint32(id[:4], uint32(sec))
+	return id, g.read(id[4:])
+}
+
+// Random128 returns an ID128 made of random bits only
+func (g *Generator) Random128() (id ID128, err error) {
+	return id, g.read(id[:])
+}
+
+// Random160 returns an ID160 made of random bits only
+func (g *Generator) Random160() (id ID160, err error) {
+	return id, g.read(id[:])
+}
+
+// New128 returns a time-ordered ID128 from the default Generator,
+// it panics if the random source fails
+func New128() ID128 { return must128(defaultGenerator.New128()) }
+
+// New160 returns a time-ordered ID160 from the default Generator,
+// it panics if the random source fails or the clock is out of range
+func New160() ID160 { return must160(defaultGenerator.New160()) }
+
+// Random128 returns a random ID128 from the default Generator,
+// it panics if the random source fails
+func Random128() ID128 { return must128(defaultGenerator.Random128()) }
+
+// Random160 returns a random ID160 from the default Generator,
+// it panics if the random source fails
+func Random160() ID160 { return must160(defaultGenerator.Random160()) }
+
+func must128(id ID128, err error) ID128 {
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+func must160(id ID160, err error) ID160 {
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+// Time returns the timestamp of a time-ordered ID128
+func (id ID128) Time() time.Time {
+	var ts [8]byte
+	copy(ts[2:], id[:6])
+	ms := int64(binary.BigEndian.Uint64(ts[:]))
+	return time.Unix(ms/1000, (ms%1000)*int64(time.Millisecond))
+}
+
+// Time returns the timestamp of a time-ordered ID160
+func (id ID160) Time() time.Time {
+	return time.Unix(Epoch160.Unix()+int64(binary.BigEndian.Uint32(id[:4])), 0)
+}
+
+// Encode returns the fixed width encoding of id, a nil enc is StdEncoding
+func (id ID128) Encode(enc *base58.Encoding) string { return encode(enc, id[:]) }
+
+// Encode returns the fixed width encoding of id, a nil enc is StdEncoding
+func (id ID160) Encode(enc *base58.Encoding) string { return encode(enc, id[:]) }
+
+// String returns the StdEncoding of id
+func (id ID128) String() string { return id.Encode(nil) }
+
+// String returns the StdEncoding of id
+func (id ID160) String() string { return id.Encode(nil) }
+
+// MarshalText implements encoding.TextMarshaler
+func (id ID128) MarshalText() ([]byte, error) { return []byte(id.String()), nil }
+
+// MarshalText implements encoding.TextMarshaler
+func (id ID160) MarshalText() ([]byte, error) { return []byte(id.String()), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (id *ID128) UnmarshalText(text []byte) (err error) {
+	*id, err = Parse128(nil, string(text))
+	return err
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (id *ID160) UnmarshalText(text []byte) (err error) {
+	*id, err = Parse160(nil, string(text))
+	return err
+}
+
+// Parse128 decodes an ID128 from s, a nil enc is StdEncoding
+func Parse128(enc *base58.Encoding, s string) (id ID128, err error) {
+	return id, decode(enc, id[:], s)
+}
+
+// Parse160 decodes an ID160 from s, a nil enc is StdEncoding
+func Parse160(enc *base58.Encoding, s string) (id ID160, err error) {
+	return id, decode(enc, id[:], s)
+}
+
+// fixedStd and fixedFlickr are the package encodings with fixed width
+// output, made once as they're the ones usually passed
+var (
+	fixedStd    = base58.StdEncoding.With(base58.WithFixedWidth())
+	fixedFlickr = base58.FlickrEncoding.With(base58.WithFixedWidth())
+)
+
+// fixed returns enc, or StdEncoding when nil, with fixed width output
+func fixed(enc *base58.Encoding) *base58.Encoding {
+	switch {
+	case enc == nil, enc == base58.StdEncoding:
+		return fixedStd
+	case enc == base58.FlickrEncoding:
+		return fixedFlickr
+	case enc.FixedWidth():
+		return enc
+	}
+	return enc.With(base58.WithFixedWidth())
+}
+
+func encode(enc *base58.Encoding, b []byte) string {
+	return fixed(enc).EncodeToString(b)
+}
+
+func decode(enc *base58.Encoding, dst []byte, s string) error {
+	enc = fixed(enc)
+	if width := enc.EncodedLen(len(dst)); len(s) != width {
+		return fmt.Errorf("id must be %d characters, have %d", width, len(s))
+	}
+
+	b, err := enc.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	if len(b) != len(dst) {
+		return fmt.Errorf("id must be %d bytes, have %d", len(dst), len(b))
+	}
+	copy(dst, b)
+	return nil
+}
@@ -0,0 +1,152 @@
+package id
+
+import (
+	"bytes"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/njones/base58"
+)
+
+func TestTimeOrder(t *testing.T) {
+	start := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var tick int
+	g := Generator{Now: func() time.Time {
+		tick++
+		return start.Add(time.Duration(tick) * 1001 * time.Millisecond)
+	}}
+
+	var s128, s160 []string
+	for i := 0; i < 500; i++ {
+		a, err := g.New128()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := g.New160()
+		if err != nil {
+			t.Fatal(err)
+		}
+		s128 = append(s128, a.String())
+		s160 = append(s160, b.String())
+
+		if len(s128[i]) != Len128 || len(s160[i]) != Len160 {
+			t.Fatalf("width want: %d/%d have: %d/%d", Len128, Len160, len(s128[i]), len(s160[i]))
+		}
+	}
+
+	if !sort.StringsAreSorted(s128) {
+		t.Errorf("ID128 strings are not in time order")
+	}
+	if !sort.StringsAreSorted(s160) {
+		t.Errorf("ID160 strings are not in time order")
+	}
+
+	id, _ := Parse128(nil, s128[0])
+	if want, have := start.Add(1001*time.Millisecond), id.Time(); !want.Equal(have) {
+		t.Errorf("ID128 time want: %v have: %v", want, have)
+	}
+	id160, _ := Parse160(nil, s160[0])
+	if want, have := start.Add(2002*time.Millisecond).Truncate(time.Second), id160.Time(); !want.Equal(have) {
+		t.Errorf("ID160 time want: %v have: %v", want, have)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	ids := []ID128{{}, {15: 1}, {0: 1}, Random128()}
+	for i := range ids[1] {
+		var id ID128
+		id[i] = 0xff
+		ids = append(ids, id)
+	}
+	ids = append(ids, ID128{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+
+	for _, enc := range []*base58.Encoding{base58.StdEncoding, base58.FlickrEncoding} {
+		for _, want := range ids {
+			s := want.Encode(enc)
+			if len(s) != Len128 {
+				t.Errorf("width want: %d have: %d (%s)", Len128, len(s), s)
+			}
+			have, err := Parse128(enc, s)
+			if err != nil {
+				t.Errorf("parse %s: %v", s, err)
+			}
+			if have != want {
+				t.Errorf("want: %x have: %x", want, have)
+			}
+		}
+	}
+
+	others := []*base58.Encoding{
+		base58.BitcoinEncoding,
+		base58.StdEncoding.With(base58.WithVersion(7)),
+		base58.NewRadixEncoding("0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"),
+	}
+	for _, enc := range others {
+		for _, want := range ids {
+			s := want.Encode(enc)
+			have, err := Parse128(enc, s)
+			if err != nil || have != want {
+				t.Errorf("%v want: %x have: %x (%v)", enc, want, have, err)
+			}
+		}
+	}
+
+	// only the package encodings keep a fixed width copy
+	for _, enc := range []*base58.Encoding{nil, base58.StdEncoding, base58.FlickrEncoding} {
+		if fixed(enc) != fixed(enc) {
+			t.Errorf("%v fixed width copy made twice", enc)
+		}
+	}
+	if enc := others[0].With(base58.WithFixedWidth()); fixed(enc) != enc {
+		t.Errorf("%v want a fixed width encoding used as is", enc)
+	}
+
+	want := Random160()
+	var have ID160
+	if err := have.UnmarshalText([]byte(want.String())); err != nil || have != want {
+		t.Errorf("want: %x have: %x (%v)", want, have, err)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	if _, err := Parse128(nil, "abc"); err == nil {
+		t.Errorf("want width error")
+	}
+	if _, err := Parse128(nil, "zzzzzzzzzzzzzzzzzzzzzz"); err == nil {
+		t.Errorf("want overflow error")
+	}
+	if _, err := Parse160(nil, "0000000000000000000000000000"); err == nil {
+		t.Errorf("want digit error")
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	for _, now := range []time.Time{Epoch160.Add(-time.Second), Epoch160.Add(1 << 32 * time.Second)} {
+		g := Generator{Now: func() time.Time { return now }}
+		if _, err := g.New160(); err != ErrTimeRange {
+			t.Errorf("%v want: %v have: %v", now, ErrTimeRange, err)
+		}
+	}
+
+	last := Epoch160.Add((1<<32 - 1) * time.Second)
+	g := Generator{Now: func() time.Time { return last }}
+	id, err := g.New160()
+	if err != nil || !id.Time().Equal(last) {
+		t.Errorf("want: %v have: %v (%v)", last, id.Time(), err)
+	}
+}
+
+func TestRandom(t *testing.T) {
+	g := Generator{Rand: bytes.NewReader(bytes.Repeat([]byte{7}, 20))}
+	id, err := g.Random160()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := bytes.Repeat([]byte{7}, 20); !bytes.Equal(id[:], want) {
+		t.Errorf("want: %x have: %x", want, id)
+	}
+	if _, err := g.Random128(); err == nil {
+		t.Errorf("want error from an exhausted random source")
+	}
+}
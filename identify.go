@@ -0,0 +1,128 @@
+package base58
+
+import "sort"
+
+// Candidate is one way a string decodes with a registered encoding,
+// as reported by Identify
+type Candidate struct {
+	Name     string    // the registered name of the encoding
+	Encoding *Encoding // the registered encoding
+
+	// Checksum is true if the encoding has a checksum, which matched,
+	// making this a much stronger candidate than one without
+	Checksum bool
+
+	// Version is the first byte of the version prefix of the encoding,
+	// which decoding checked and removed, or the first decoded byte, often
+	// a version byte, when the encoding has no prefix
+	Version byte
+
+	PayloadLen int    // the number of decoded bytes, without the checksum
+	Format     string // the likely format, empty when not recognized
+}
+
+// Identify tries every registered encoding on s and returns the
+// candidates that decode it, those with a matching checksum first, then
+// those with a recognized format, then in name order.
+func Identify(s string) []Candidate {
+	var candidates []Candidate
+	for _, name := range Names() {
+		enc, _ := Lookup(name)
+		if c, ok := identify(name, enc, s); ok {
+			candidates = append(candidates, c)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Checksum != b.Checksum {
+			return a.Checksum
+		}
+		return a.Format != "" && b.Format == ""
+	})
+	return candidates
+}
+
+// identify returns the Candidate for s decoded with enc, registered as
+// name, and whether enc decodes it
+func identify(name string, enc *Encoding, s string) (Candidate, bool) {
+	b, err := enc.DecodeString(s)
+	if err != nil || len(b) == 0 {
+		return Candidate{}, false
+	}
+
+	version := b[0]
+	if len(enc.version) > 0 {
+		version = enc.version[0]
+	}
+	return Candidate{
+		Name:       name,
+		Encoding:   enc,
+		Checksum:   enc.HasChecksum(),
+		Version:    version,
+		PayloadLen: len(b),
+		Format:     enc.format(b),
+	}, true
+}
+
+// bitcoinVersions are the formats of 21-byte bitcoin check payloads
+var bitcoinVersions = map[byte]string{
+	0x00: "P2PKH address",
+	0x05: "P2SH address",
+	0x6f: "testnet P2PKH address",
+	0xc4: "testnet P2SH address",
+}
+
+// extendedKeys are the formats of 78-byte BIP32 payloads, by their
+// 4-byte version
+var extendedKeys = map[string]string{
+	"\x04\x88\xb2\x1e": "xpub extended public key",
+	"\x04\x88\xad\xe4": "xprv extended private key",
+	"\x04\x9d\x7c\xb2": "ypub extended public key",
+	"\x04\x9d\x78\x78": "yprv extended private key",
+	"\x04\xb2\x47\x46": "zpub extended public key",
+	"\x04\xb2\x43\x0c": "zprv extended private key",
+	"\x04\x35\x87\xcf": "tpub testnet extended public key",
+	"\x04\x35\x83\x94": "tprv testnet extended private key",
+}
+
+// format returns the likely format of the decoded payload b, based on
+// the alphabet and checksum of enc and the length and version of b
+func (enc *Encoding) format(b []byte) string {
+	sha256d4 := enc.checkNum == 4 && enc.checkName == "sha256d" && len(enc.version) == 0
+
+	switch {
+	case enc.encode == bitcoinAlphabet && sha256d4:
+		switch {
+		case len(b) == 21:
+			return bitcoinVersions[b[0]]
+		case (len(b) == 33 || len(b) == 34 && b[33] == 0x01) && b[0] == 0x80:
+			return "WIF private key"
+		case (len(b) == 33 || len(b) == 34 && b[33] == 0x01) && b[0] == 0xef:
+			return "testnet WIF private key"
+		case len(b) == 78:
+			return extendedKeys[string(b[:4])]
+		}
+	case enc.encode == rippleAlphabet && sha256d4:
+		switch {
+		case len(b) == 21 && b[0] == 0x00:
+			return "XRP account address"
+		case len(b) == 17 && b[0] == 0x21:
+			return "XRP secret seed"
+		}
+	case enc.checkName == "cb58" && enc.checkNum == 4:
+		return "CB58 (Avalanche) data"
+	case enc.encode == bitcoinAlphabet && enc.checkNum == 0 && len(enc.version) == 0:
+		switch {
+		case len(b) == 34 && b[0] == 0x12 && b[1] == 0x20:
+			return "CIDv0 (IPFS sha2-256 multihash)"
+		case len(b) == 32:
+			return "Solana public key"
+		case len(b) == 64:
+			return "Solana signature or keypair"
+		}
+	case enc.encode == flickrAlphabet && enc.checkNum == 0 && len(b) <= 8:
+		return "Flickr short ID"
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package base58
+
+import "testing"
+
+func TestIdentify(t *testing.T) {
+	for _, test := range []struct {
+		s, name, format string
+		payloadLen      int
+		checksum        bool
+	}{
+		{"1AGNa15ZQXAZUgFiqJ2i7Z2DPU2J6hW62i", "bitcoin-check", "P2PKH address", 21, true},
+		{"3CMNFxN1oHBc4R1EpboAL5yzHGgE611Xou", "bitcoin-check", "P2SH address", 21, true},
+		{"mo9ncXisMeAoXwqcV5EWuyncbmCcQN4rVs", "bitcoin-check", "testnet P2PKH address", 21, true},
+		{"5Kd3NBUAdUnhyzenEwVLy9pBKxSwXvE9FMPyR4UKZvpe6E3AgLr", "bitcoin-check", "WIF private key", 33, true},
+		{"Kz6UJmQACJmLtaQj5A3JAge4kVTNQ8gbvXuwbmCj7bsaabudb3RD", "bitcoin-check", "WIF private key", 34, true},
+		{"cTpB4YiyKiBcPxnefsDpbnDxFDffjqJob8wGCEDXxgQ7zQoMXJdH", "bitcoin-check", "testnet WIF private key", 34, true},
+		{"xpub661MyMwAqRbcFtXgS5sYJABqqG9YLmC4Q1Rdap9gSE8NqtwybGhePY2gZ29ESFjqJoCu1Rupje8YtGqsefD265TMg7usUDFdp6W1EGMcet8", "bitcoin-check", "xpub extended public key", 78, true},
+		{"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh", "ripple-check", "XRP account address", 21, true},
+		{"QmYwAPJzv5CZsnA625s3Xf2nemtYgPpHdWEz79ojWnPbdG", "bitcoin", "CIDv0 (IPFS sha2-256 multihash)", 34, false},
+		{"11111111111111111111111111111111", "bitcoin", "Solana public key", 32, false},
+		{"6hKMCS", "flickr", "Flickr short ID", 4, false},
+	} {
+		candidates := Identify(test.s)
+		if len(candidates) == 0 {
+			t.Errorf("%s: no candidates", test.s)
+			continue
+		}
+
+		have := candidates[0]
+		if have.Name != test.name || have.Format != test.format || have.PayloadLen != test.payloadLen {
+			t.Errorf("%s want: %s %q %d have: %s %q %d", test.s, test.name, test.format, test.payloadLen, have.Name, have.Format, have.PayloadLen)
+		}
+		if have.Checksum != test.checksum {
+			t.Errorf("%s checksum want: %t have: %t", test.s, test.checksum, have.Checksum)
+		}
+	}
+
+	if have := Identify("6hKMCS")[0]; have.Version != 0xce {
+		t.Errorf("version want: 0xce have: %#x", have.Version)
+	}
+
+	versioned := StdEncoding.With(WithVersion(0x41, 0x42))
+	if have, ok := identify("versioned", versioned, versioned.EncodeToString([]byte{0xce})); !ok || have.Version != 0x41 || have.PayloadLen != 1 {
+		t.Errorf("version want: 0x41 1 have: %#x %d (%t)", have.Version, have.PayloadLen, ok)
+	}
+
+	if have := Identify("0OIl"); len(have) != 0 {
+		t.Errorf("want no candidates have: %v", have)
+	}
+}
@@ -0,0 +1,153 @@
+package base58
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+// ErrOverflow is returned when a decoded value doesn't fit in a uint64
+const ErrOverflow = errString("value overflows uint64")
+
+// bigDigits are the digits used by big.Int for bases up to big.MaxBase
+const bigDigits = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// numeric reports if integers can be written as plain digits, rather
+// than going through the byte encoding for checksums, check digits,
+// versions and fixed widths
+func (enc *Encoding) numeric() bool {
+	return enc.checkNum == 0 && !enc.checkDigit && !enc.fixedWidth && len(enc.version) == 0
+}
+
+// EncodeUint64 returns the encoding of v as a number, so with
+// FlickrEncoding 3471391110 is "6hKMCS". Encodings with a checksum or
+// version encode the big-endian bytes of v, trimmed of leading zeros, and
+// fixed width encodings all 8 bytes.
+func (enc *Encoding) EncodeUint64(v uint64) string {
+	var buf [64]byte
+	return string(enc.AppendUint64(buf[:0], v))
+}
+
+// AppendUint64 appends the EncodeUint64 encoding of v to dst and
+// returns the extended buffer. It doesn't allocate when dst has room.
+func (enc *Encoding) AppendUint64(dst []byte, v uint64) []byte {
+	if !enc.numeric() {
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], v)
+		src := b[:]
+		if !enc.fixedWidth {
+			for len(src) > 1 && src[0] == 0 {
+				src = src[1:]
+			}
+		}
+		return append(dst, enc.EncodeToString(src)...)
+	}
+
+	var buf [64]byte
+	i := len(buf)
+	for {
+		i--
+		buf[i] = enc.encode[v%uint64(enc.radix)]
+		v /= uint64(enc.radix)
+		if v == 0 {
+			break
+		}
+	}
+	return append(dst, buf[i:]...)
+}
+
+// DecodeUint64 returns the number encoded in s by EncodeUint64, or
+// ErrOverflow if it doesn't fit in a uint64
+func (enc *Encoding) DecodeUint64(s string) (uint64, error) {
+	if len(s) == 0 {
+		return 0, ErrZeroLength
+	}
+
+	if !enc.numeric() {
+		if s == "0" {
+			return 0, nil // how Encode writes all zero input
+		}
+		b, err := enc.DecodeString(s)
+		if err != nil {
+			return 0, err
+		}
+		for len(b) > 0 && b[0] == 0 {
+			b = b[1:]
+		}
+		if len(b) > 8 {
+			return 0, ErrOverflow
+		}
+		var v uint64
+		for _, c := range b {
+			v = v<<8 | uint64(c)
+		}
+		return v, nil
+	}
+
+	const max = ^uint64(0)
+	var v uint64
+	for i := 0; i < len(s); i++ {
+		d := enc.codec.Value(s[i])
+		if d == -1 {
+			return 0, InvalidDigitError(s[i])
+		}
+		if v > (max-uint64(d))/uint64(enc.radix) {
+			return 0, ErrOverflow
+		}
+		v = v*uint64(enc.radix) + uint64(d)
+	}
+	return v, nil
+}
+
+// EncodeBigInt returns the encoding of the absolute value of x as a
+// number. Encodings with a checksum, version or fixed width, or a radix
+// above big.MaxBase, encode x.Bytes().
+func (enc *Encoding) EncodeBigInt(x *big.Int) string {
+	if !enc.numeric() || enc.radix > big.MaxBase {
+		return enc.EncodeToString(x.Bytes())
+	}
+
+	text := []byte(new(big.Int).Abs(x).Text(enc.radix))
+	for i, c := range text {
+		text[i] = enc.encode[bigDigitValue(c)]
+	}
+	return string(text)
+}
+
+// DecodeBigInt returns the number encoded in s by EncodeBigInt
+func (enc *Encoding) DecodeBigInt(s string) (*big.Int, error) {
+	if len(s) == 0 {
+		return nil, ErrZeroLength
+	}
+
+	if !enc.numeric() || enc.radix > big.MaxBase {
+		if s == "0" {
+			return new(big.Int), nil // how Encode writes all zero input
+		}
+		b, err := enc.DecodeString(s)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Int).SetBytes(b), nil
+	}
+
+	text := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		d := enc.codec.Value(s[i])
+		if d == -1 {
+			return nil, InvalidDigitError(s[i])
+		}
+		text[i] = bigDigits[d]
+	}
+	x, _ := new(big.Int).SetString(string(text), enc.radix)
+	return x, nil
+}
+
+func bigDigitValue(c byte) byte {
+	switch {
+	case c >= '0' && c <= '9':
+		return c - '0'
+	case c >= 'a' && c <= 'z':
+		return c - 'a' + 10
+	}
+	return c - 'A' + 36
+}
@@ -0,0 +1,117 @@
+package base58
+
+import (
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestFlickrUint64(t *testing.T) {
+	for _, pair := range base58FlickrTestPairs {
+		if have := FlickrEncoding.EncodeUint64(pair.Big); have != pair.String {
+			t.Errorf("encode want: %s have: %s", pair.String, have)
+		}
+
+		have, err := FlickrEncoding.DecodeUint64(pair.String)
+		if err != nil {
+			t.Errorf("decode [%s]: %v", pair.String, err)
+		}
+		if have != pair.Big {
+			t.Errorf("decode want: %d have: %d", pair.Big, have)
+		}
+	}
+}
+
+func TestUint64RoundTrip(t *testing.T) {
+	fixed := NewEncoding(bitcoinAlphabet, WithFixedWidth())
+	versioned := StdEncoding.With(WithVersion(9))
+	checked := StdEncoding.With(WithCheckDigit())
+	for _, enc := range []*Encoding{StdEncoding, FlickrEncoding, BitcoinEncoding, fixed, versioned, checked} {
+		for _, v := range []uint64{0, 1, 57, 58, 255, 256, 1 << 32, math.MaxUint64 - 1, math.MaxUint64} {
+			s := enc.EncodeUint64(v)
+			have, err := enc.DecodeUint64(s)
+			if err != nil {
+				t.Errorf("decode %d [%s]: %v", v, s, err)
+			}
+			if have != v {
+				t.Errorf("want: %d have: %d", v, have)
+			}
+			if enc == fixed && len(s) != 11 {
+				t.Errorf("fixed width want: 11 have: %d", len(s))
+			}
+		}
+	}
+
+	if have := StdEncoding.EncodeUint64(0); have != "1" {
+		t.Errorf("zero want: 1 have: %s", have)
+	}
+
+	if have, want := versioned.EncodeUint64(12345), versioned.EncodeToString([]byte{0x30, 0x39}); have != want {
+		t.Errorf("version want: %s have: %s", want, have)
+	}
+	if have, want := checked.EncodeUint64(12345), checked.EncodeToString([]byte{0x30, 0x39}); have != want {
+		t.Errorf("check digit want: %s have: %s", want, have)
+	}
+	if _, err := versioned.DecodeUint64(StdEncoding.EncodeUint64(12345)); err != ErrInvalidVersion {
+		t.Errorf("want: %v have: %v", ErrInvalidVersion, err)
+	}
+}
+
+func TestUint64Errors(t *testing.T) {
+	max := StdEncoding.EncodeUint64(math.MaxUint64)
+	if _, err := StdEncoding.DecodeUint64(max + "1"); err != ErrOverflow {
+		t.Errorf("want: %v have: %v", ErrOverflow, err)
+	}
+	if _, err := StdEncoding.DecodeUint64("jpXCZedGfVR"); err != ErrOverflow {
+		t.Errorf("want: %v have: %v", ErrOverflow, err)
+	}
+	if _, err := StdEncoding.DecodeUint64("0"); err == nil {
+		t.Errorf("want invalid digit error")
+	}
+	if _, err := StdEncoding.DecodeUint64(""); err != ErrZeroLength {
+		t.Errorf("want: %v have: %v", ErrZeroLength, err)
+	}
+}
+
+func TestUint64Allocs(t *testing.T) {
+	dst := make([]byte, 0, 16)
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = StdEncoding.AppendUint64(dst[:0], 3471391110)
+		StdEncoding.DecodeUint64("6hKMCS")
+	})
+	if allocs != 0 {
+		t.Errorf("want: 0 allocs have: %v", allocs)
+	}
+}
+
+func TestBigInt(t *testing.T) {
+	for _, pair := range base58FlickrTestPairs {
+		x := new(big.Int).SetUint64(pair.Big)
+		if have := FlickrEncoding.EncodeBigInt(x); have != pair.String {
+			t.Errorf("encode want: %s have: %s", pair.String, have)
+		}
+	}
+
+	x, _ := new(big.Int).SetString("123456789012345678901234567890123456789012345678901234567890", 10)
+	for _, enc := range []*Encoding{StdEncoding, FlickrEncoding, BitcoinEncoding, StdEncoding.With(WithVersion(9))} {
+		s := enc.EncodeBigInt(x)
+		have, err := enc.DecodeBigInt(s)
+		if err != nil {
+			t.Errorf("decode [%s]: %v", s, err)
+		}
+		if have.Cmp(x) != 0 {
+			t.Errorf("want: %v have: %v", x, have)
+		}
+	}
+
+	// the numeric encoding matches the byte encoding without leading zeros
+	if want, have := StdEncoding.EncodeToString(x.Bytes()), StdEncoding.EncodeBigInt(x); want != have {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+	if have, err := BitcoinEncoding.DecodeBigInt(BitcoinEncoding.EncodeBigInt(new(big.Int))); err != nil || have.Sign() != 0 {
+		t.Errorf("zero want: 0 have: %v (%v)", have, err)
+	}
+	if _, err := StdEncoding.DecodeBigInt("l"); err == nil {
+		t.Errorf("want invalid digit error")
+	}
+}
@@ -0,0 +1,268 @@
+// Package obfuscate maps numbers to short, non-sequential strings and
+// back, with the Hashids algorithm. The alphabet is shuffled by a salt,
+// so without the salt the strings don't show the numbers or their order.
+// The default alphabet is the bitcoin base58 alphabet, and with the same
+// alphabet, salt and minimum length the strings are the same as those of
+// other Hashids libraries. It's obfuscation, not encryption, see package
+// fpe for that.
+package obfuscate
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/njones/base58"
+)
+
+type errString string
+
+func (e errString) Error() string {
+	return string(e)
+}
+
+// ErrInvalidAlphabet is returned by New for an alphabet that is too
+// short, or has characters that aren't printable ASCII
+const ErrInvalidAlphabet = errString("invalid obfuscate alphabet")
+
+// ErrNoNumbers is returned by Encode when there are no numbers
+const ErrNoNumbers = errString("no numbers to encode")
+
+// ErrInvalidString is returned by Decode for a string that Encode
+// doesn't make
+const ErrInvalidString = errString("invalid obfuscated string")
+
+const (
+	minAlphabetLen = 16
+	sepDiv         = 3.5
+	guardDiv       = 12
+	defaultSeps    = "cfhistuCFHISTU"
+)
+
+// Encoding obfuscates numbers with a salt
+type Encoding struct {
+	alphabet  string
+	seps      string
+	guards    string
+	salt      string
+	minLength int
+}
+
+type options struct {
+	alphabet  string
+	minLength int
+}
+
+// Option is the functional option type used to configure an Encoding
+type Option func(*options)
+
+// WithAlphabet replaces the base58 alphabet with alphabet, which must
+// have at least 16 unique characters, all printable ASCII without spaces
+func WithAlphabet(alphabet string) Option {
+	return func(o *options) {
+		o.alphabet = alphabet
+	}
+}
+
+// WithMinLength pads strings to at least n characters
+func WithMinLength(n int) Option {
+	return func(o *options) {
+		o.minLength = n
+	}
+}
+
+// New returns an Encoding using salt
+func New(salt string, opts ...Option) (*Encoding, error) {
+	o := options{alphabet: base58.StdEncoding.Alphabet()}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var alphabet []byte
+	for i := 0; i < len(o.alphabet); i++ {
+		if strings.IndexByte(string(alphabet), o.alphabet[i]) < 0 {
+			alphabet = append(alphabet, o.alphabet[i])
+		}
+	}
+	if len(alphabet) < minAlphabetLen {
+		return nil, fmt.Errorf("%w: %d unique characters, need %d", ErrInvalidAlphabet, len(alphabet), minAlphabetLen)
+	}
+	if _, err := base58.NewRadixEncodingE(string(alphabet)); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidAlphabet, err)
+	}
+
+	// the separators are the default separators in the alphabet, which
+	// are taken out of it, then the guards come from the alphabet
+	var seps []byte
+	for i := 0; i < len(defaultSeps); i++ {
+		if j := strings.IndexByte(string(alphabet), defaultSeps[i]); j >= 0 {
+			seps = append(seps, defaultSeps[i])
+			alphabet = append(alphabet[:j], alphabet[j+1:]...)
+		}
+	}
+	shuffle(seps, salt)
+
+	if len(seps) == 0 || float64(len(alphabet))/float64(len(seps)) > sepDiv {
+		n := int(math.Ceil(float64(len(alphabet)) / sepDiv))
+		if n == 1 {
+			n++
+		}
+		if n > len(seps) {
+			diff := n - len(seps)
+			seps = append(seps, alphabet[:diff]...)
+			alphabet = alphabet[diff:]
+		} else {
+			seps = seps[:n]
+		}
+	}
+	shuffle(alphabet, salt)
+
+	var guards []byte
+	n := int(math.Ceil(float64(len(alphabet)) / guardDiv))
+	if len(alphabet) < 3 {
+		guards, seps = seps[:n], seps[n:]
+	} else {
+		guards, alphabet = alphabet[:n], alphabet[n:]
+	}
+
+	return &Encoding{
+		alphabet:  string(alphabet),
+		seps:      string(seps),
+		guards:    string(guards),
+		salt:      salt,
+		minLength: o.minLength,
+	}, nil
+}
+
+// Encode returns the string for numbers, it's unique for the numbers in
+// that order
+func (e *Encoding) Encode(numbers ...uint64) (string, error) {
+	if len(numbers) == 0 {
+		return "", ErrNoNumbers
+	}
+
+	var numbersHash uint64
+	for i, n := range numbers {
+		numbersHash += n % uint64(i+100)
+	}
+
+	alphabet := []byte(e.alphabet)
+	lottery := alphabet[numbersHash%uint64(len(alphabet))]
+	result := []byte{lottery}
+
+	for i, n := range numbers {
+		e.shuffleFor(alphabet, lottery)
+		digits := hash(n, alphabet)
+		result = append(result, digits...)
+
+		if i+1 < len(numbers) {
+			n %= uint64(digits[0]) + uint64(i)
+			result = append(result, e.seps[n%uint64(len(e.seps))])
+		}
+	}
+
+	if len(result) < e.minLength {
+		guard := e.guards[(numbersHash+uint64(result[0]))%uint64(len(e.guards))]
+		result = append([]byte{guard}, result...)
+
+		if len(result) < e.minLength {
+			guard = e.guards[(numbersHash+uint64(result[2]))%uint64(len(e.guards))]
+			result = append(result, guard)
+		}
+	}
+
+	half := len(alphabet) / 2
+	for len(result) < e.minLength {
+		shuffle(alphabet, string(alphabet))
+		result = append(append(append([]byte{}, alphabet[half:]...), result...), alphabet[:half]...)
+		if excess := len(result) - e.minLength; excess > 0 {
+			result = result[excess/2 : excess/2+e.minLength]
+		}
+	}
+
+	return string(result), nil
+}
+
+// Decode returns the numbers s was made from
+func (e *Encoding) Decode(s string) ([]uint64, error) {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return strings.ContainsRune(e.guards, r) })
+	i := 0
+	if len(parts) == 2 || len(parts) == 3 {
+		i = 1
+	}
+	if len(parts) == 0 || len(parts[i]) < 2 {
+		return nil, ErrInvalidString
+	}
+
+	alphabet := []byte(e.alphabet)
+	lottery := parts[i][0]
+
+	var numbers []uint64
+	for _, digits := range strings.FieldsFunc(parts[i][1:], func(r rune) bool { return strings.ContainsRune(e.seps, r) }) {
+		e.shuffleFor(alphabet, lottery)
+		n, ok := unhash(digits, alphabet)
+		if !ok {
+			return nil, ErrInvalidString
+		}
+		numbers = append(numbers, n)
+	}
+
+	// only one string decodes to the numbers, the one Encode makes
+	if have, err := e.Encode(numbers...); err != nil || have != s {
+		return nil, ErrInvalidString
+	}
+	return numbers, nil
+}
+
+// shuffleFor shuffles alphabet for the next number, using the lottery
+// character, the salt and alphabet itself
+func (e *Encoding) shuffleFor(alphabet []byte, lottery byte) {
+	buffer := append(append([]byte{lottery}, e.salt...), alphabet...)
+	shuffle(alphabet, string(buffer[:len(alphabet)]))
+}
+
+// hash returns n in the radix of alphabet, with alphabet as the digits,
+// which are in a different order for each number
+func hash(n uint64, alphabet []byte) []byte {
+	radix := uint64(len(alphabet))
+	var b [64]byte
+	i := len(b)
+	for {
+		i--
+		b[i] = alphabet[n%radix]
+		if n /= radix; n == 0 {
+			break
+		}
+	}
+	return b[i:]
+}
+
+// unhash returns the number hash makes s from, and false when s has
+// characters outside alphabet or the number overflows a uint64
+func unhash(s string, alphabet []byte) (uint64, bool) {
+	radix := uint64(len(alphabet))
+	var n uint64
+	for i := 0; i < len(s); i++ {
+		d := strings.IndexByte(string(alphabet), s[i])
+		if d < 0 || n > (math.MaxUint64-uint64(d))/radix {
+			return 0, false
+		}
+		n = n*radix + uint64(d)
+	}
+	return n, true
+}
+
+// shuffle is the Hashids consistent shuffle of alphabet by salt
+func shuffle(alphabet []byte, salt string) {
+	if len(salt) == 0 {
+		return
+	}
+	for i, v, p := len(alphabet)-1, 0, 0; i > 0; i-- {
+		v %= len(salt)
+		n := int(salt[v])
+		p += n
+		j := (n + v + p) % i
+		alphabet[i], alphabet[j] = alphabet[j], alphabet[i]
+		v++
+	}
+}
@@ -0,0 +1,94 @@
+package obfuscate
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/njones/base58"
+)
+
+// hashidsAlphabet is the default alphabet of other Hashids libraries
+const hashidsAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890"
+
+func TestHashidsCompatible(t *testing.T) {
+	for _, test := range []struct {
+		minLength int
+		numbers   []uint64
+		want      string
+	}{
+		{0, []uint64{12345}, "NkK9"},
+		{0, []uint64{1, 2, 3}, "laHquq"},
+		{8, []uint64{1}, "gB0NV05e"},
+	} {
+		e, err := New("this is my salt", WithAlphabet(hashidsAlphabet), WithMinLength(test.minLength))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if have, err := e.Encode(test.numbers...); err != nil || have != test.want {
+			t.Errorf("%v want: %s have: %s (%v)", test.numbers, test.want, have, err)
+		}
+	}
+}
+
+func TestEncoding(t *testing.T) {
+	e, _ := New("base58 salt", WithMinLength(6))
+
+	seen := map[string]bool{}
+	for _, numbers := range [][]uint64{
+		{0}, {1}, {2}, {58}, {1 << 32}, {^uint64(0)}, {1, 2, 3}, {3, 2, 1}, {0, 0}, {42, ^uint64(0), 7},
+	} {
+		s, err := e.Encode(numbers...)
+		if err != nil || len(s) < 6 || seen[s] {
+			t.Fatalf("%v have: %s (%v)", numbers, s, err)
+		}
+		seen[s] = true
+
+		if strings.Trim(s, base58.StdEncoding.Alphabet()) != "" {
+			t.Errorf("%s want only base58 characters", s)
+		}
+		if have, err := e.Decode(s); err != nil || !reflect.DeepEqual(have, numbers) {
+			t.Errorf("%s want: %v have: %v (%v)", s, numbers, have, err)
+		}
+	}
+
+	other, _ := New("other salt", WithMinLength(6))
+	s, _ := e.Encode(1)
+	if have, _ := other.Encode(1); have == s {
+		t.Errorf("want different strings for different salts have: %s", s)
+	}
+	if _, err := other.Decode(s); !errors.Is(err, ErrInvalidString) {
+		t.Errorf("%s want: %v have: %v", s, ErrInvalidString, err)
+	}
+
+	if _, err := e.Encode(); err != ErrNoNumbers {
+		t.Errorf("want: %v have: %v", ErrNoNumbers, err)
+	}
+	for _, alphabet := range []string{"abc", hashidsAlphabet + " ", hashidsAlphabet + "\xe9", hashidsAlphabet + "\x00"} {
+		if _, err := New("", WithAlphabet(alphabet)); !errors.Is(err, ErrInvalidAlphabet) {
+			t.Errorf("%q want: %v have: %v", alphabet, ErrInvalidAlphabet, err)
+		}
+	}
+
+	// a number past a uint64 is invalid, not wrapped around
+	if have, err := e.Decode(strings.Repeat("2", 20)); !errors.Is(err, ErrInvalidString) {
+		t.Errorf("want: %v have: %v (%v)", ErrInvalidString, have, err)
+	}
+}
+
+func TestHash(t *testing.T) {
+	alphabet := []byte(base58.StdEncoding.Alphabet())
+	for _, n := range []uint64{0, 1, 57, 58, 1 << 32, ^uint64(0)} {
+		// the digits are those of the integer encodings
+		if want, have := base58.StdEncoding.EncodeUint64(n), string(hash(n, alphabet)); have != want {
+			t.Errorf("%d want: %s have: %s", n, want, have)
+		}
+		if have, ok := unhash(string(hash(n, alphabet)), alphabet); !ok || have != n {
+			t.Errorf("%d have: %d (%v)", n, have, ok)
+		}
+	}
+	if _, ok := unhash(strings.Repeat("z", 12), alphabet); ok {
+		t.Errorf("want overflow")
+	}
+}
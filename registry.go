@@ -0,0 +1,82 @@
+package base58
+
+import (
+	"sort"
+	"sync"
+)
+
+const rippleAlphabet = "rpshnaf39wBUDNEGHJKLM4PQRST7VWXYZ2bcdeCg65jkm8oFqi1tuvAxyz"
+
+// addressMaxLen is the input limit of the registered address-oriented
+// encodings, enough for the 111 characters of a BIP32 extended key
+const addressMaxLen = 128
+
+var (
+	registryMu sync.RWMutex
+	registry   = make(map[string]*Encoding)
+)
+
+func init() {
+	Register("bitcoin", StdEncoding)
+	Register("bitcoin-check", BitcoinEncoding.With(WithMaxEncodedLen(addressMaxLen)))
+	Register("flickr", FlickrEncoding)
+	Register("ripple", NewEncoding(rippleAlphabet))
+	Register("ripple-check", NewEncoding(rippleAlphabet, WithChecksum(4), WithMaxEncodedLen(addressMaxLen)))
+	Register("cb58", NewEncoding(bitcoinAlphabet, WithChecksum(4), withChecksumName("cb58"), WithMaxEncodedLen(addressMaxLen)))
+}
+
+// Register makes an encoding available by name to Lookup, so encodings
+// can be chosen by configuration. The predefined names are "bitcoin",
+// "bitcoin-check", "flickr", "ripple", "ripple-check" and "cb58", where
+// the ones with a checksum are limited to 128 character input, as used by
+// addresses and keys. If Register is called twice with the same name or
+// if enc is nil, it panics.
+func Register(name string, enc *Encoding) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if enc == nil {
+		panic("base58: Register encoding is nil")
+	}
+	if _, dup := registry[name]; dup {
+		panic("base58: Register called twice for encoding " + name)
+	}
+	registry[name] = enc
+}
+
+// Lookup returns the encoding registered with name
+func Lookup(name string) (enc *Encoding, ok bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	enc, ok = registry[name]
+	return enc, ok
+}
+
+// Names returns a sorted list of the registered encoding names
+func Names() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// registeredName returns the name enc is registered with, the first in
+// sorted order if it's registered more than once, or else the name of an
+// Equal encoding, as the registered encodings may only add input limits
+func registeredName(enc *Encoding) (string, bool) {
+	names := Names()
+	for _, name := range names {
+		if have, _ := Lookup(name); have == enc {
+			return name, true
+		}
+	}
+	for _, name := range names {
+		if have, _ := Lookup(name); have.Equal(enc) {
+			return name, true
+		}
+	}
+	return "", false
+}
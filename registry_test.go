@@ -0,0 +1,85 @@
+package base58
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	for name, want := range map[string]*Encoding{
+		"bitcoin": StdEncoding,
+		"flickr":  FlickrEncoding,
+	} {
+		if have, ok := Lookup(name); !ok || have != want {
+			t.Errorf("%s want: %p have: %p", name, want, have)
+		}
+	}
+
+	// the registered address encodings only add an input limit
+	if have, ok := Lookup("bitcoin-check"); !ok || !have.Equal(BitcoinEncoding) {
+		t.Errorf("bitcoin-check want: %v have: %v", BitcoinEncoding, have)
+	}
+
+	if _, ok := Lookup("base64"); ok {
+		t.Errorf("want no encoding for an unknown name")
+	}
+
+	names := Names()
+	if len(names) < 6 || names[0] != "bitcoin" {
+		t.Errorf("want sorted names have: %v", names)
+	}
+}
+
+func TestRegister(t *testing.T) {
+	enc := NewEncoding(flickrAlphabet, WithChecksum(4))
+	Register("flickr-check-test", enc)
+	if have, ok := Lookup("flickr-check-test"); !ok || have != enc {
+		t.Errorf("want: %p have: %p", enc, have)
+	}
+
+	for _, test := range []struct {
+		name string
+		enc  *Encoding
+	}{
+		{"flickr", enc},
+		{"nil-test", nil},
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s want panic", test.name)
+				}
+			}()
+			Register(test.name, test.enc)
+		}()
+	}
+}
+
+func TestRegisteredEncodings(t *testing.T) {
+	ripple, _ := Lookup("ripple-check")
+
+	// the genesis account of the XRP ledger
+	b, err := ripple.DecodeString("rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh")
+	if err != nil {
+		t.Fatalf("ripple decode: %v", err)
+	}
+	if want := "00b5f762798a53d543a014caf8b297cff8f2f937e8"; hex.EncodeToString(b) != want {
+		t.Errorf("ripple want: %s have: %x", want, b)
+	}
+	if have := ripple.EncodeToString(b); have != "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh" {
+		t.Errorf("ripple encode have: %s", have)
+	}
+
+	cb58, _ := Lookup("cb58")
+	data := []byte("Hello world")
+	sum := sha256.Sum256(data)
+	want := StdEncoding.EncodeToString(append(append([]byte{}, data...), sum[28:]...))
+	if have := cb58.EncodeToString(data); have != want {
+		t.Errorf("cb58 want: %s have: %s", want, have)
+	}
+	if have, err := cb58.DecodeString(want); err != nil || !bytes.Equal(have, data) {
+		t.Errorf("cb58 want: %s have: %s (%v)", data, have, err)
+	}
+}
@@ -0,0 +1,218 @@
+package base58
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/njones/base58/core"
+)
+
+// ErrInvalidSpec is returned by ParseSpec when the spec can't be parsed
+const ErrInvalidSpec = errString("invalid encoding spec")
+
+// specAlphabets are the alphabets that have a name in a spec
+var specAlphabets = []struct{ name, alphabet string }{
+	{"bitcoin", bitcoinAlphabet},
+	{"flickr", flickrAlphabet},
+	{"ripple", rippleAlphabet},
+}
+
+// checksumFuncs are the checksum algorithms that have a name in a spec,
+// the codec uses their sums directly so they don't allocate
+var checksumFuncs = map[string]namedChecksum{
+	"sha256d": {sha256.Size, sha256dSum},
+	"sha256":  {sha256.Size, sha256Sum},
+	"cb58":    {4, cb58Sum},
+}
+
+// namedChecksum is a checksum algorithm of checksumFuncs with a size
+// byte sum
+type namedChecksum struct {
+	size int
+	sum  func([]byte) core.Sum
+}
+
+// bytes returns the sum of b as a slice, as WithChecksumFunc takes
+func (c namedChecksum) bytes(b []byte) []byte {
+	sum := c.sum(b)
+	return sum[:c.size]
+}
+
+// sha256dSum is the default sha256(sha256()) checksum
+func sha256dSum(b []byte) (sum core.Sum) {
+	h := sha256.Sum256(b)
+	h = sha256.Sum256(h[:])
+	copy(sum[:], h[:])
+	return sum
+}
+
+// sha256Sum is a single sha256 of the data
+func sha256Sum(b []byte) (sum core.Sum) {
+	h := sha256.Sum256(b)
+	copy(sum[:], h[:])
+	return sum
+}
+
+// cb58Sum is the Avalanche CB58 checksum, the last 4 bytes of a single
+// sha256 of the data
+func cb58Sum(b []byte) (sum core.Sum) {
+	h := sha256.Sum256(b)
+	copy(sum[:], h[len(h)-4:])
+	return sum
+}
+
+// withChecksumName sets one of the named checksumFuncs
+func withChecksumName(name string) Option {
+	return func(enc *Encoding) {
+		enc.checkFunc = checksumFuncs[name].bytes
+		enc.checkName = name
+		enc.customCheck = false
+		enc.hmac = nil
+	}
+}
+
+// ParseSpec returns the encoding described by spec, a list of
+// semicolon separated key=value pairs, for example:
+//
+//	alphabet=flickr;checksum=sha256d:4;version=0x00
+//
+// The alphabet is one of the names bitcoin, flickr or ripple, or a
+// quoted custom alphabet whose length sets the radix. The checksum is an
+// algorithm name, one of sha256d, sha256 or cb58, and a length. The
+// version is a hex prefix, fixed=true sets WithFixedWidth,
+// checkdigit=luhn sets WithCheckDigit and maxencoded and maxdecoded set
+// the input limits. Only the alphabet is required.
+func ParseSpec(spec string) (*Encoding, error) {
+	var alphabet string
+	var options []Option
+
+	for rest := strings.TrimSpace(spec); rest != ""; {
+		eq := strings.IndexByte(rest, '=')
+		if eq < 0 {
+			return nil, fmt.Errorf("%w: missing '=' in %q", ErrInvalidSpec, rest)
+		}
+		key := strings.TrimSpace(rest[:eq])
+		rest = strings.TrimSpace(rest[eq+1:])
+
+		var value string
+		if strings.HasPrefix(rest, `"`) {
+			quoted, err := strconv.QuotedPrefix(rest)
+			if err != nil {
+				return nil, fmt.Errorf("%w: bad quoted %s value", ErrInvalidSpec, key)
+			}
+			value, _ = strconv.Unquote(quoted)
+			rest = strings.TrimSpace(rest[len(quoted):])
+			if rest != "" && rest[0] != ';' {
+				return nil, fmt.Errorf("%w: missing ';' after %s", ErrInvalidSpec, key)
+			}
+		} else {
+			end := strings.IndexByte(rest, ';')
+			if end < 0 {
+				end = len(rest)
+			}
+			value = strings.TrimSpace(rest[:end])
+			rest = rest[end:]
+		}
+		rest = strings.TrimPrefix(rest, ";")
+
+		switch key {
+		case "alphabet":
+			alphabet = value
+			for _, a := range specAlphabets {
+				if value == a.name {
+					alphabet = a.alphabet
+				}
+			}
+		case "checksum":
+			i := strings.LastIndexByte(value, ':')
+			if i < 0 {
+				return nil, fmt.Errorf("%w: checksum must be name:length (%q)", ErrInvalidSpec, value)
+			}
+			name := value[:i]
+			if _, ok := checksumFuncs[name]; !ok {
+				return nil, fmt.Errorf("%w: unknown checksum algorithm (%q)", ErrInvalidSpec, name)
+			}
+			n, err := strconv.Atoi(value[i+1:])
+			if err != nil {
+				return nil, fmt.Errorf("%w: bad checksum length (%q)", ErrInvalidSpec, value[i+1:])
+			}
+			options = append(options, withChecksumName(name), WithChecksum(n))
+		case "version":
+			v, err := hex.DecodeString(strings.TrimPrefix(strings.TrimPrefix(value, "0x"), "0X"))
+			if err != nil || len(v) == 0 {
+				return nil, fmt.Errorf("%w: bad version (%q)", ErrInvalidSpec, value)
+			}
+			options = append(options, WithVersion(v...))
+		case "fixed":
+			fixed, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("%w: bad fixed value (%q)", ErrInvalidSpec, value)
+			}
+			if fixed {
+				options = append(options, WithFixedWidth())
+			}
+		case "checkdigit":
+			if value != "luhn" {
+				return nil, fmt.Errorf("%w: unknown check digit algorithm (%q)", ErrInvalidSpec, value)
+			}
+			options = append(options, WithCheckDigit())
+		case "maxencoded", "maxdecoded":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("%w: bad %s value (%q)", ErrInvalidSpec, key, value)
+			}
+			if key == "maxencoded" {
+				options = append(options, WithMaxEncodedLen(n))
+			} else {
+				options = append(options, WithMaxDecodedLen(n))
+			}
+		default:
+			return nil, fmt.Errorf("%w: unknown key (%q)", ErrInvalidSpec, key)
+		}
+	}
+
+	if alphabet == "" {
+		return nil, fmt.Errorf("%w: missing alphabet", ErrInvalidSpec)
+	}
+	return NewRadixEncodingE(alphabet, options...)
+}
+
+// Spec returns the description of enc that ParseSpec turns back into an
+// equal encoding. A checksum function set with WithChecksumFunc has no
+// name, so it's written as "custom", which ParseSpec doesn't accept.
+func (enc *Encoding) Spec() string {
+	alphabet := strconv.Quote(enc.encode)
+	for _, a := range specAlphabets {
+		if enc.encode == a.alphabet {
+			alphabet = a.name
+		}
+	}
+
+	spec := []string{"alphabet=" + alphabet}
+	if enc.checkNum > 0 {
+		name := enc.checkName
+		if name == "" {
+			name = "custom"
+		}
+		spec = append(spec, fmt.Sprintf("checksum=%s:%d", name, enc.checkNum))
+	}
+	if len(enc.version) > 0 {
+		spec = append(spec, "version=0x"+hex.EncodeToString(enc.version))
+	}
+	if enc.fixedWidth {
+		spec = append(spec, "fixed=true")
+	}
+	if enc.checkDigit {
+		spec = append(spec, "checkdigit=luhn")
+	}
+	if enc.maxEncodedLen > 0 {
+		spec = append(spec, "maxencoded="+strconv.Itoa(enc.maxEncodedLen))
+	}
+	if enc.maxDecodedLen > 0 {
+		spec = append(spec, "maxdecoded="+strconv.Itoa(enc.maxDecodedLen))
+	}
+	return strings.Join(spec, ";")
+}
@@ -0,0 +1,121 @@
+package base58
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSpecRoundTrip(t *testing.T) {
+	for _, spec := range []string{
+		"alphabet=bitcoin",
+		"alphabet=bitcoin;checksum=sha256d:4",
+		"alphabet=flickr;checksum=sha256d:4;version=0x00",
+		"alphabet=ripple;checksum=sha256:2;version=0x0488b21e;fixed=true",
+		"alphabet=bitcoin;checksum=cb58:4;maxencoded=128",
+		"alphabet=flickr;maxencoded=64;maxdecoded=32",
+		"alphabet=flickr;fixed=true;checkdigit=luhn",
+		`alphabet="0123456789abcdef"`,
+		`alphabet="!\"#$%&'()*+,-./:;<=>?@[\\]^_` + "`" + `{|}~ABCDEFGHIJKLMNOPQRSTUVWXYZabcd";fixed=true`,
+	} {
+		enc, err := ParseSpec(spec)
+		if err != nil {
+			t.Errorf("parse %s: %v", spec, err)
+			continue
+		}
+		if have := enc.Spec(); have != spec {
+			t.Errorf("want: %s have: %s", spec, have)
+		}
+		if back, err := ParseSpec(enc.Spec()); err != nil || !back.Equal(enc) {
+			t.Errorf("round trip %s: %v", spec, err)
+		}
+	}
+
+	for name, want := range map[string]string{
+		"bitcoin-check": "alphabet=bitcoin;checksum=sha256d:4;maxencoded=128",
+		"cb58":          "alphabet=bitcoin;checksum=cb58:4;maxencoded=128",
+		"ripple":        "alphabet=ripple",
+	} {
+		enc, _ := Lookup(name)
+		if have := enc.Spec(); have != want {
+			t.Errorf("%s want: %s have: %s", name, want, have)
+		}
+	}
+
+	custom := BitcoinEncoding.With(WithChecksumFunc(sha256d))
+	if want, have := "alphabet=bitcoin;checksum=custom:4", custom.Spec(); have != want {
+		t.Errorf("want: %s have: %s", want, have)
+	}
+}
+
+func TestParseSpecEquivalent(t *testing.T) {
+	enc, err := ParseSpec(" alphabet = bitcoin ; checksum = sha256d:4 ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !enc.Equal(BitcoinEncoding) {
+		t.Errorf("want an encoding equal to BitcoinEncoding, have: %v", enc)
+	}
+}
+
+func TestParseSpecErrors(t *testing.T) {
+	for _, test := range []struct {
+		spec string
+		want error
+	}{
+		{"", ErrInvalidSpec},
+		{"checksum=sha256d:4", ErrInvalidSpec},
+		{"alphabet", ErrInvalidSpec},
+		{"alphabet=bitcoin;colour=blue", ErrInvalidSpec},
+		{"alphabet=bitcoin;checksum=md5:4", ErrInvalidSpec},
+		{"alphabet=bitcoin;checksum=custom:4", ErrInvalidSpec},
+		{"alphabet=bitcoin;checksum=sha256d", ErrInvalidSpec},
+		{"alphabet=bitcoin;version=zz", ErrInvalidSpec},
+		{"alphabet=bitcoin;fixed=maybe", ErrInvalidSpec},
+		{"alphabet=bitcoin;checkdigit=damm", ErrInvalidSpec},
+		{"alphabet=bitcoin;maxencoded=-1", ErrInvalidSpec},
+		{`alphabet="abc`, ErrInvalidSpec},
+		{"alphabet=x", ErrInvalidAlphabet},
+		{"alphabet=aab", ErrInvalidAlphabet},
+		{"alphabet=bitcoin;checksum=cb58:8", ErrInvalidOption},
+	} {
+		if _, err := ParseSpec(test.spec); !errors.Is(err, test.want) {
+			t.Errorf("%q want: %v have: %v", test.spec, test.want, err)
+		}
+	}
+}
+
+func TestVersion(t *testing.T) {
+	p2pkh := BitcoinEncoding.With(WithVersion(0x00))
+	payload := []byte{0x65, 0xa1, 0x60, 0x59, 0x86, 0x4a, 0x2f, 0xdb, 0xc7, 0xc9, 0x9a, 0x47, 0x23, 0xa8, 0x39, 0x5b, 0xc6, 0xf1, 0x88, 0xeb}
+
+	s := p2pkh.EncodeToString(payload)
+	if want := "1AGNa15ZQXAZUgFiqJ2i7Z2DPU2J6hW62i"; s != want {
+		t.Errorf("want: %s have: %s", want, s)
+	}
+	if have, err := p2pkh.DecodeString(s); err != nil || !bytes.Equal(have, payload) {
+		t.Errorf("want: %x have: %x (%v)", payload, have, err)
+	}
+
+	p2sh := BitcoinEncoding.With(WithVersion(0x05))
+	if _, err := p2sh.DecodeString(s); err != ErrInvalidVersion {
+		t.Errorf("want: %v have: %v", ErrInvalidVersion, err)
+	}
+
+	// a zero version byte is still a version, zero data keeps it
+	flickr, err := ParseSpec("alphabet=flickr;version=0x00")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, enc := range []*Encoding{StdEncoding.With(WithVersion(0)), flickr} {
+		s := enc.EncodeToString([]byte{0})
+		if have, err := enc.DecodeString(s); err != nil || !bytes.Equal(have, []byte{0}) {
+			t.Errorf("%s want: 00 have: %x (%v)", s, have, err)
+		}
+	}
+}
+
+// sha256d is the named sha256d checksum as a WithChecksumFunc function
+func sha256d(b []byte) []byte {
+	return checksumFuncs["sha256d"].bytes(b)
+}
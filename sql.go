@@ -0,0 +1,122 @@
+package base58
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Storage selects how a Column is written to a database
+type Storage int
+
+const (
+	// StoreText stores the encoded string, for text/varchar columns
+	StoreText Storage = iota
+
+	// StoreBytes stores the decoded bytes, for bytea/blob columns
+	StoreBytes
+)
+
+// Column is a database/sql Scanner and driver.Valuer for base58 data.
+// Bytes holds the decoded value, Encoding the encoding used for text
+// storage (StdEncoding when nil) and Storage how the value is stored.
+// Scanning text is validated with the checksum settings of Encoding.
+// A NULL column scans to nil Bytes and nil Bytes are stored as NULL.
+type Column struct {
+	Bytes    []byte
+	Encoding *Encoding
+	Storage  Storage
+}
+
+func (c Column) encoding() *Encoding {
+	if c.Encoding == nil {
+		return StdEncoding
+	}
+	return c.Encoding
+}
+
+// Value implements driver.Valuer
+func (c Column) Value() (driver.Value, error) {
+	if c.Bytes == nil {
+		return nil, nil
+	}
+	switch c.Storage {
+	case StoreText:
+		if c.encoding().codec.TooLargeToEncode(len(c.Bytes)) {
+			return nil, ErrInputTooLarge
+		}
+		return encodeText(c.encoding(), c.Bytes), nil
+	case StoreBytes:
+		return append([]byte{}, c.Bytes...), nil
+	}
+	return nil, fmt.Errorf("unknown base58 column storage (%d)", c.Storage)
+}
+
+// Scan implements sql.Scanner. Text is decoded, while raw bytes are
+// copied as is when Storage is StoreBytes and decoded as text otherwise,
+// since some drivers return text columns as []byte. Bytes is nil when
+// Scan fails.
+func (c *Column) Scan(src interface{}) (err error) {
+	switch v := src.(type) {
+	case nil:
+		c.Bytes = nil
+	case string:
+		c.Bytes, err = scanText(c.encoding(), v)
+	case []byte:
+		if c.Storage == StoreBytes {
+			c.Bytes = append([]byte{}, v...)
+			return nil
+		}
+		c.Bytes, err = scanText(c.encoding(), string(v))
+	default:
+		c.Bytes = nil
+		return fmt.Errorf("unsupported base58 scan type (%T)", src)
+	}
+	if err != nil {
+		c.Bytes = nil
+	}
+	return err
+}
+
+// Value implements driver.Valuer, storing the StdEncoding text
+func (b Bytes) Value() (driver.Value, error) {
+	return Column{Bytes: b}.Value()
+}
+
+// Scan implements sql.Scanner for StdEncoding text
+func (b *Bytes) Scan(src interface{}) error {
+	var c Column
+	if err := c.Scan(src); err != nil {
+		return err
+	}
+	*b = c.Bytes
+	return nil
+}
+
+// Value implements driver.Valuer, storing the StdEncoding text
+func (h Hash32) Value() (driver.Value, error) { return encodeText(StdEncoding, h[:]), nil }
+
+// Scan implements sql.Scanner for StdEncoding text
+func (h *Hash32) Scan(src interface{}) error { return scanFixed(h[:], src, "Hash32") }
+
+// Value implements driver.Valuer, storing the StdEncoding text
+func (h Hash20) Value() (driver.Value, error) { return encodeText(StdEncoding, h[:]), nil }
+
+// Scan implements sql.Scanner for StdEncoding text
+func (h *Hash20) Scan(src interface{}) error { return scanFixed(h[:], src, "Hash20") }
+
+func scanText(enc *Encoding, s string) ([]byte, error) {
+	if len(s) == 0 {
+		return []byte{}, nil
+	}
+	return enc.DecodeString(s)
+}
+
+func scanFixed(dst []byte, src interface{}, name string) error {
+	switch v := src.(type) {
+	case string:
+		return decodeFixed(dst, []byte(v), name)
+	case []byte:
+		return decodeFixed(dst, v, name)
+	}
+	return fmt.Errorf("unsupported %s scan type (%T)", name, src)
+}
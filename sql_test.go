@@ -0,0 +1,129 @@
+package base58
+
+import (
+	"bytes"
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"io"
+	"testing"
+)
+
+// memDriver is a single column, single row database/sql driver. Any Exec
+// stores its argument and any Query returns it, as the database would.
+type memDriver struct{ value driver.Value }
+
+func (d *memDriver) Open(string) (driver.Conn, error)    { return d, nil }
+func (d *memDriver) Prepare(string) (driver.Stmt, error) { return d, nil }
+func (d *memDriver) Close() error                        { return nil }
+func (d *memDriver) Begin() (driver.Tx, error)           { return nil, io.EOF }
+func (d *memDriver) NumInput() int                       { return -1 }
+
+func (d *memDriver) Exec(args []driver.Value) (driver.Result, error) {
+	d.value = args[0]
+	return driver.RowsAffected(1), nil
+}
+
+func (d *memDriver) Query([]driver.Value) (driver.Rows, error) {
+	return &memRows{value: d.value}, nil
+}
+
+type memRows struct {
+	value driver.Value
+	done  bool
+}
+
+func (r *memRows) Columns() []string { return []string{"v"} }
+func (r *memRows) Close() error      { return nil }
+
+func (r *memRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	r.done = true
+	dest[0] = r.value
+	return nil
+}
+
+var testDriver = new(memDriver)
+
+func init() { sql.Register("base58mem", testDriver) }
+
+func TestColumnRoundTrip(t *testing.T) {
+	db, err := sql.Open("base58mem", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	payload, _ := hex.DecodeString("0065a16059864a2fdbc7c99a4723a8395bc6f188eb")
+
+	tests := []struct {
+		name   string
+		column Column
+		stored driver.Value
+	}{
+		{"text", Column{Bytes: payload, Encoding: BitcoinEncoding}, "1AGNa15ZQXAZUgFiqJ2i7Z2DPU2J6hW62i"},
+		{"bytes", Column{Bytes: payload, Encoding: BitcoinEncoding, Storage: StoreBytes}, payload},
+		{"null", Column{Storage: StoreBytes}, nil},
+		{"zero", Column{Bytes: make([]byte, 20), Encoding: BitcoinEncoding}, "111111111111111111117K4nzc"},
+	}
+
+	for _, test := range tests {
+		if _, err := db.Exec("INSERT", test.column); err != nil {
+			t.Fatalf("%s exec: %v", test.name, err)
+		}
+		if s, ok := test.stored.(string); ok && testDriver.value != s {
+			t.Errorf("%s stored want: %v have: %v", test.name, s, testDriver.value)
+		}
+
+		have := Column{Encoding: test.column.Encoding, Storage: test.column.Storage}
+		if err := db.QueryRow("SELECT").Scan(&have); err != nil {
+			t.Fatalf("%s scan: %v", test.name, err)
+		}
+		if !bytes.Equal(have.Bytes, test.column.Bytes) || (have.Bytes == nil) != (test.column.Bytes == nil) {
+			t.Errorf("%s want: %x have: %x", test.name, test.column.Bytes, have.Bytes)
+		}
+	}
+}
+
+func TestColumnScanChecksum(t *testing.T) {
+	c := Column{Encoding: BitcoinEncoding}
+	if err := c.Scan("1Cwvi9VZSR3sXBS1pG59UowQRVc"); err != ErrInvalidChecksum {
+		t.Errorf("want: %v have: %v", ErrInvalidChecksum, err)
+	}
+	if c.Bytes != nil {
+		t.Errorf("want nil Bytes after a failed scan have: %x", c.Bytes)
+	}
+	if err := c.Scan([]byte("1AGNa15ZQXAZUgFiqJ2i7Z2DPU2J6hW62i")); err != nil {
+		t.Errorf("scan text as bytes: %v", err)
+	}
+	if err := c.Scan(42); err == nil {
+		t.Errorf("want error for unsupported type")
+	}
+	if c.Bytes != nil {
+		t.Errorf("want nil Bytes after a failed scan have: %x", c.Bytes)
+	}
+}
+
+func TestHashScanValue(t *testing.T) {
+	var h Hash32
+	h[31] = 1
+	v, err := h.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var back Hash32
+	if err := back.Scan(v); err != nil {
+		t.Fatalf("scan: %v", err)
+	}
+	if back != h {
+		t.Errorf("want: %x have: %x", h, back)
+	}
+
+	var short Hash20
+	if err := short.Scan(v); err == nil {
+		t.Errorf("want error scanning a Hash32 into a Hash20")
+	}
+}
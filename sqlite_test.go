@@ -0,0 +1,92 @@
+//go:build sqlite
+
+// The SQLite tests need cgo and the github.com/mattn/go-sqlite3 module,
+// run them with: go test -tags sqlite
+
+package base58
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/hex"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestColumnSQLite(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1) // every connection to :memory: is a new database
+
+	if _, err := db.Exec("CREATE TABLE ids (name TEXT, txt TEXT, blb BLOB, any)"); err != nil {
+		t.Fatal(err)
+	}
+
+	payload, _ := hex.DecodeString("0065a16059864a2fdbc7c99a4723a8395bc6f188eb")
+
+	tests := []struct {
+		name    string
+		bytes   []byte
+		enc     *Encoding
+		typeof  string // the SQLite type of the value in the untyped column
+		storage Storage
+	}{
+		{"text", payload, BitcoinEncoding, "text", StoreText},
+		{"bytes", payload, BitcoinEncoding, "blob", StoreBytes},
+		{"zero", make([]byte, 20), BitcoinEncoding, "text", StoreText},
+		{"std", payload, nil, "text", StoreText},
+		{"null", nil, nil, "null", StoreBytes},
+	}
+
+	for _, test := range tests {
+		col := Column{Bytes: test.bytes, Encoding: test.enc, Storage: test.storage}
+		if _, err := db.Exec("INSERT INTO ids VALUES (?, ?, ?, ?)", test.name, col, col, col); err != nil {
+			t.Fatalf("%s insert: %v", test.name, err)
+		}
+
+		var typeof string
+		if err := db.QueryRow("SELECT typeof(any) FROM ids WHERE name = ?", test.name).Scan(&typeof); err != nil {
+			t.Fatalf("%s typeof: %v", test.name, err)
+		}
+		if typeof != test.typeof {
+			t.Errorf("%s stored type want: %s have: %s", test.name, test.typeof, typeof)
+		}
+
+		// TEXT and BLOB affinity convert the value on insert, and the
+		// driver reads TEXT as a string and BLOB as []byte
+		for _, column := range []string{"txt", "blb", "any"} {
+			have := Column{Encoding: test.enc, Storage: test.storage}
+			if err := db.QueryRow("SELECT "+column+" FROM ids WHERE name = ?", test.name).Scan(&have); err != nil {
+				t.Fatalf("%s %s scan: %v", test.name, column, err)
+			}
+			if !bytes.Equal(have.Bytes, test.bytes) || (have.Bytes == nil) != (test.bytes == nil) {
+				t.Errorf("%s %s want: %x have: %x", test.name, column, test.bytes, have.Bytes)
+			}
+		}
+	}
+
+	var h, back Hash32
+	h[31] = 1
+	if _, err := db.Exec("INSERT INTO ids (name, txt) VALUES ('hash', ?)", h); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.QueryRow("SELECT txt FROM ids WHERE name = 'hash'").Scan(&back); err != nil || back != h {
+		t.Errorf("hash want: %x have: %x (%v)", h, back, err)
+	}
+
+	var b Bytes
+	if _, err := db.Exec("INSERT INTO ids (name, txt) VALUES ('bad', '1Cwvi9VZSR3sXBS1pG59UowQRVc')"); err != nil {
+		t.Fatal(err)
+	}
+	have := Column{Bytes: payload, Encoding: BitcoinEncoding}
+	if err := db.QueryRow("SELECT txt FROM ids WHERE name = 'bad'").Scan(&have); err == nil || have.Bytes != nil {
+		t.Errorf("bad checksum want an error and nil Bytes have: %x (%v)", have.Bytes, err)
+	}
+	if err := db.QueryRow("SELECT blb FROM ids WHERE name = 'std'").Scan(&b); err != nil || !bytes.Equal(b, payload) {
+		t.Errorf("Bytes want: %x have: %x (%v)", payload, []byte(b), err)
+	}
+}
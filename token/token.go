@@ -0,0 +1,149 @@
+// Package token makes compact signed tokens, like invite codes, as
+// base58 strings using StdEncoding. A token holds a version, flags, an
+// optional expiry, the payload and an ed25519 signature of all of them:
+//
+//	version | flags | expiry (4 bytes, optional) | payload | signature (64 bytes)
+//
+// The expiry is big-endian unix seconds. Tokens are URL-safe and
+// unpadded, and take about 90 characters more than the payload alone.
+package token
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"fmt"
+	"time"
+
+	"github.com/njones/base58"
+)
+
+type errString string
+
+func (e errString) Error() string {
+	return string(e)
+}
+
+// ErrInvalidToken is returned by Verify for a string that isn't a token
+const ErrInvalidToken = errString("invalid token")
+
+// ErrInvalidSignature is returned by Verify when the signature doesn't
+// match the public key
+const ErrInvalidSignature = errString("invalid token signature")
+
+// ErrExpired is returned by Verify for a token past its expiry
+const ErrExpired = errString("the token has expired")
+
+// ErrExpiryRange is returned by Sign for an expiry the 4 byte unix
+// seconds can't hold, before 1970 or after 2106
+const ErrExpiryRange = errString("the token expiry is out of range")
+
+// Version is the version of the tokens Sign makes
+const Version = 1
+
+// MaxLen is the length of the longest token Verify decodes, longer input
+// returns base58.ErrInputTooLarge without any work, as decoding is
+// quadratic in the length. It fits a payload of about 1400 bytes.
+const MaxLen = 2048
+
+// flagExpiry is set when the token has an expiry
+const flagExpiry = 1 << 0
+
+// headerLen is the length of the version and flags
+const headerLen = 2
+
+var encoding = base58.StdEncoding.With(base58.WithMaxEncodedLen(MaxLen))
+
+type options struct {
+	expiry time.Time
+	now    func() time.Time
+}
+
+// Option is the functional option type used by Sign and Verify
+type Option func(*options)
+
+// WithExpiry sets the time after which Verify rejects the token, to the
+// second, from 1970 to early 2106. It's a Sign option.
+func WithExpiry(t time.Time) Option {
+	return func(o *options) {
+		o.expiry = t
+	}
+}
+
+// WithNow sets the clock Verify checks the expiry against, time.Now by
+// default. It's a Verify option.
+func WithNow(now func() time.Time) Option {
+	return func(o *options) {
+		o.now = now
+	}
+}
+
+// Sign returns a token holding payload, signed with priv. It returns
+// base58.ErrInputTooLarge if the token would be longer than MaxLen, and
+// ErrExpiryRange for an expiry out of range.
+func Sign(priv ed25519.PrivateKey, payload []byte, opts ...Option) (string, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	msg := []byte{Version, 0}
+	if !o.expiry.IsZero() {
+		if sec := o.expiry.Unix(); sec < 0 || sec > 1<<32-1 {
+			return "", ErrExpiryRange
+		}
+		msg[1] |= flagExpiry
+		msg = binary.BigEndian.AppendUint32(msg, uint32(o.expiry.Unix()))
+	}
+	msg = append(msg, payload...)
+
+	if encoding.EncodedLen(len(msg)+ed25519.SignatureSize) > MaxLen {
+		return "", base58.ErrInputTooLarge
+	}
+	return encoding.EncodeToString(append(msg, ed25519.Sign(priv, msg)...)), nil
+}
+
+// Verify returns the payload of the token s after checking that it was
+// signed with the private key of pub and hasn't expired.
+func Verify(pub ed25519.PublicKey, s string, opts ...Option) ([]byte, error) {
+	o := options{now: time.Now}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	b, err := encoding.DecodeString(s)
+	if err != nil {
+		if err == base58.ErrInputTooLarge {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if len(b) < headerLen+ed25519.SignatureSize {
+		return nil, fmt.Errorf("%w: too short", ErrInvalidToken)
+	}
+	if b[0] != Version {
+		return nil, fmt.Errorf("%w: unknown version %d", ErrInvalidToken, b[0])
+	}
+
+	msg, sig := b[:len(b)-ed25519.SignatureSize], b[len(b)-ed25519.SignatureSize:]
+	flags, payload := msg[1], msg[headerLen:]
+	if flags&^flagExpiry != 0 {
+		return nil, fmt.Errorf("%w: unknown flags %#x", ErrInvalidToken, flags)
+	}
+
+	var expiry time.Time
+	if flags&flagExpiry != 0 {
+		if len(payload) < 4 {
+			return nil, fmt.Errorf("%w: too short for the expiry", ErrInvalidToken)
+		}
+		expiry = time.Unix(int64(binary.BigEndian.Uint32(payload)), 0)
+		payload = payload[4:]
+	}
+
+	if !ed25519.Verify(pub, msg, sig) {
+		return nil, ErrInvalidSignature
+	}
+	if !expiry.IsZero() && o.now().After(expiry) {
+		return nil, ErrExpired
+	}
+	return payload, nil
+}
@@ -0,0 +1,80 @@
+package token
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/njones/base58"
+)
+
+func TestToken(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(bytes.NewReader(make([]byte, 32)))
+	otherPub, _, _ := ed25519.GenerateKey(bytes.NewReader(bytes.Repeat([]byte{1}, 32)))
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	clock := func(t time.Time) Option { return WithNow(func() time.Time { return t }) }
+
+	invite, err := Sign(priv, []byte("invite:team-7"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expiring, _ := Sign(priv, []byte("invite:team-7"), WithExpiry(now.Add(time.Hour)))
+	empty, _ := Sign(priv, nil)
+
+	if strings.ContainsAny(invite, "+/=_-0OIl") {
+		t.Errorf("want only base58 digits have: %s", invite)
+	}
+
+	tampered := []byte(invite)
+	tampered[len(tampered)/2] ^= 'a' ^ 'b'
+
+	for _, test := range []struct {
+		name  string
+		pub   ed25519.PublicKey
+		token string
+		now   time.Time
+		want  string
+		err   error
+	}{
+		{"invite", pub, invite, now, "invite:team-7", nil},
+		{"empty", pub, empty, now, "", nil},
+		{"expiring", pub, expiring, now, "invite:team-7", nil},
+		{"expired", pub, expiring, now.Add(2 * time.Hour), "", ErrExpired},
+		{"other key", otherPub, invite, now, "", ErrInvalidSignature},
+		{"tampered", pub, string(tampered), now, "", ErrInvalidSignature},
+		{"truncated", pub, invite[:20], now, "", ErrInvalidToken},
+		{"not base58", pub, "0OIl", now, "", ErrInvalidToken},
+		{"version", pub, base58.StdEncoding.EncodeToString(append([]byte{2, 0}, make([]byte, 64)...)), now, "", ErrInvalidToken},
+		{"too long", pub, strings.Repeat("2", MaxLen+1), now, "", base58.ErrInputTooLarge},
+	} {
+		have, err := Verify(test.pub, test.token, clock(test.now))
+		if !errors.Is(err, test.err) || string(have) != test.want {
+			t.Errorf("%s want: %q %v have: %q %v", test.name, test.want, test.err, have, err)
+		}
+	}
+
+	if _, err := Sign(priv, make([]byte, MaxLen)); err != base58.ErrInputTooLarge {
+		t.Errorf("want: %v have: %v", base58.ErrInputTooLarge, err)
+	}
+
+	for _, expiry := range []time.Time{time.Unix(-1, 0), time.Unix(1<<32, 0)} {
+		if _, err := Sign(priv, nil, WithExpiry(expiry)); err != ErrExpiryRange {
+			t.Errorf("%v want: %v have: %v", expiry, ErrExpiryRange, err)
+		}
+	}
+	last := time.Unix(1<<32-1, 0)
+	s, err := Sign(priv, nil, WithExpiry(last))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Verify(pub, s, clock(last)); err != nil {
+		t.Errorf("last expiry want: nil have: %v", err)
+	}
+	if _, err := Verify(pub, s, clock(last.Add(time.Second))); err != ErrExpired {
+		t.Errorf("last expiry want: %v have: %v", ErrExpired, err)
+	}
+}
@@ -0,0 +1,44 @@
+package base58
+
+// Transcode converts s from the encoding from to the encoding to. When
+// both have the same radix and options, like FlickrEncoding and
+// StdEncoding, each digit maps to the digit of the same value in linear
+// time, and the checksum isn't checked as it carries over unchanged.
+// Otherwise s is decoded with from, checking its checksum and version,
+// then encoded with to, which adds its own, so this also adds, strips
+// or replaces a checksum.
+func Transcode(from, to *Encoding, s string) (string, error) {
+	if len(s) == 0 {
+		return "", ErrZeroLength
+	}
+	if from.codec.TooLarge(len(s)) {
+		return "", ErrInputTooLarge
+	}
+	if s == "0" && from.codec.Value('0') == -1 {
+		return to.EncodeToString([]byte{0}), nil // how Encode writes all zero input
+	}
+
+	if !from.sameValues(to) {
+		b, err := from.DecodeString(s)
+		if err != nil {
+			return "", err
+		}
+		if to.codec.TooLargeToEncode(len(b)) {
+			return "", ErrInputTooLarge
+		}
+		return to.EncodeToString(b), nil
+	}
+
+	if to.codec.TooLarge(len(s)) {
+		return "", ErrInputTooLarge
+	}
+	buf := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		d := from.codec.Value(s[i])
+		if d == -1 {
+			return "", InvalidDigitError(s[i])
+		}
+		buf[i] = to.encode[d]
+	}
+	return string(buf), nil
+}
@@ -0,0 +1,53 @@
+package base58
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestTranscode(t *testing.T) {
+	rippleCheck, _ := Lookup("ripple-check")
+	flickrCheck := FlickrEncoding.With(WithChecksum(4))
+
+	const address = "1AGNa15ZQXAZUgFiqJ2i7Z2DPU2J6hW62i"
+	payload, _ := BitcoinEncoding.DecodeString(address)
+
+	const genesis = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+	account, _ := rippleCheck.DecodeString(genesis)
+
+	for _, test := range []struct {
+		name     string
+		from, to *Encoding
+		s, want  string
+	}{
+		{"map", StdEncoding, FlickrEncoding, "11Hey", "11hDY"},
+		{"map checksum", BitcoinEncoding, flickrCheck, address, flickrCheck.EncodeToString(payload)},
+		{"map back", flickrCheck, BitcoinEncoding, flickrCheck.EncodeToString(payload), address},
+		{"ripple", rippleCheck, BitcoinEncoding, genesis, BitcoinEncoding.EncodeToString(account)},
+		{"strip", BitcoinEncoding, StdEncoding, address, StdEncoding.EncodeToString(payload)},
+		{"add", StdEncoding, BitcoinEncoding, StdEncoding.EncodeToString(payload), address},
+		{"zero", StdEncoding, FlickrEncoding, StdEncoding.EncodeToString([]byte{0}), FlickrEncoding.EncodeToString([]byte{0})},
+		{"zero checksum", StdEncoding, BitcoinEncoding, "0", BitcoinEncoding.EncodeToString([]byte{0})},
+	} {
+		have, err := Transcode(test.from, test.to, test.s)
+		if err != nil || have != test.want {
+			t.Errorf("%s want: %s have: %s (%v)", test.name, test.want, have, err)
+		}
+	}
+
+	for _, test := range []struct {
+		from, to *Encoding
+		s        string
+		want     error
+	}{
+		{StdEncoding, FlickrEncoding, "", ErrZeroLength},
+		{StdEncoding, FlickrEncoding, "1l1", InvalidDigitError('l')},
+		{BitcoinEncoding, StdEncoding, "1AGNa15ZQXAZUgFiqJ2i7Z2DPU2J6hW62j", ErrInvalidChecksum},
+		{BitcoinEncoding, StdEncoding.With(WithMaxDecodedLen(20)), address, ErrInputTooLarge},
+		{FlickrEncoding, StdEncoding.With(WithMaxEncodedLen(5)), "2C6LHCj6o9NVQN5", ErrInputTooLarge},
+	} {
+		if _, err := Transcode(test.from, test.to, test.s); !errors.Is(err, test.want) {
+			t.Errorf("%q want: %v have: %v", test.s, test.want, err)
+		}
+	}
+}
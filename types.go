@@ -0,0 +1,184 @@
+package base58
+
+import (
+	"encoding/hex"
+	"fmt"
+	"strconv"
+)
+
+// Bytes is a byte slice that marshals to and from its StdEncoding base58
+// form, so it can be embedded in structs that round-trip through JSON,
+// YAML, TOML or any other encoding.TextMarshaler aware format.
+type Bytes []byte
+
+// Hash32 is a fixed size 32-byte value, such as a sha256 digest or an
+// ed25519 public key, that marshals to and from its StdEncoding form
+type Hash32 [32]byte
+
+// Hash20 is a fixed size 20-byte value, such as a ripemd160 digest, that
+// marshals to and from its StdEncoding form
+type Hash20 [20]byte
+
+// String returns the StdEncoding of b
+func (b Bytes) String() string { return encodeText(StdEncoding, b) }
+
+// Format implements fmt.Formatter. The %s and %v verbs write the base58
+// form, %q writes it quoted and %x and %X write the raw bytes as hex.
+func (b Bytes) Format(f fmt.State, verb rune) { formatText(f, verb, b) }
+
+// MarshalText implements encoding.TextMarshaler
+func (b Bytes) MarshalText() ([]byte, error) { return []byte(encodeText(StdEncoding, b)), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler, an empty text
+// results in a nil Bytes value
+func (b *Bytes) UnmarshalText(text []byte) error {
+	if len(text) == 0 {
+		*b = nil
+		return nil
+	}
+	dec, err := StdEncoding.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	*b = dec
+	return nil
+}
+
+// MarshalJSON implements json.Marshaler, a nil Bytes value is written
+// as null
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	if b == nil {
+		return []byte("null"), nil
+	}
+	return marshalJSON(b), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler
+func (b *Bytes) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+	text, err := unmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+	return b.UnmarshalText(text)
+}
+
+// String returns the StdEncoding of h
+func (h Hash32) String() string { return encodeText(StdEncoding, h[:]) }
+
+// Format implements fmt.Formatter, see Bytes.Format for the verbs
+func (h Hash32) Format(f fmt.State, verb rune) { formatText(f, verb, h[:]) }
+
+// MarshalText implements encoding.TextMarshaler
+func (h Hash32) MarshalText() ([]byte, error) { return []byte(encodeText(StdEncoding, h[:])), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (h *Hash32) UnmarshalText(text []byte) error { return decodeFixed(h[:], text, "Hash32") }
+
+// MarshalJSON implements json.Marshaler
+func (h Hash32) MarshalJSON() ([]byte, error) { return marshalJSON(h[:]), nil }
+
+// UnmarshalJSON implements json.Unmarshaler
+func (h *Hash32) UnmarshalJSON(data []byte) error {
+	text, err := unmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+	return h.UnmarshalText(text)
+}
+
+// String returns the StdEncoding of h
+func (h Hash20) String() string { return encodeText(StdEncoding, h[:]) }
+
+// Format implements fmt.Formatter, see Bytes.Format for the verbs
+func (h Hash20) Format(f fmt.State, verb rune) { formatText(f, verb, h[:]) }
+
+// MarshalText implements encoding.TextMarshaler
+func (h Hash20) MarshalText() ([]byte, error) { return []byte(encodeText(StdEncoding, h[:])), nil }
+
+// UnmarshalText implements encoding.TextUnmarshaler
+func (h *Hash20) UnmarshalText(text []byte) error { return decodeFixed(h[:], text, "Hash20") }
+
+// MarshalJSON implements json.Marshaler
+func (h Hash20) MarshalJSON() ([]byte, error) { return marshalJSON(h[:]), nil }
+
+// UnmarshalJSON implements json.Unmarshaler
+func (h *Hash20) UnmarshalJSON(data []byte) error {
+	text, err := unmarshalJSON(data)
+	if err != nil {
+		return err
+	}
+	return h.UnmarshalText(text)
+}
+
+// encodeText returns the encoding of b using enc. Encode writes a lone
+// "0" for all zero input, which can't be decoded, so the codec is used
+// directly, which writes a zero digit for each byte and then the checksum
+func encodeText(enc *Encoding, b []byte) string {
+	if len(b) == 0 {
+		return ""
+	}
+	buf := make([]byte, enc.EncodedLen(len(b)))
+	n, err := enc.codec.Encode(buf, b, make([]uint64, enc.codec.AccLen(len(b))))
+	if err != nil {
+		panic(err)
+	}
+	return string(buf[:n])
+}
+
+// decodeFixed decodes text into dst which must be filled exactly
+func decodeFixed(dst, text []byte, name string) error {
+	dec, err := StdEncoding.DecodeString(string(text))
+	if err != nil {
+		return err
+	}
+	if len(dec) != len(dst) {
+		return fmt.Errorf("decoded length %d does not fit a %s (%d bytes)", len(dec), name, len(dst))
+	}
+	copy(dst, dec)
+	return nil
+}
+
+func marshalJSON(b []byte) []byte {
+	return strconv.AppendQuote(nil, encodeText(StdEncoding, b))
+}
+
+func unmarshalJSON(data []byte) ([]byte, error) {
+	s, err := strconv.Unquote(string(data))
+	if err != nil || len(data) == 0 || data[0] != '"' {
+		return nil, fmt.Errorf("base58 JSON value must be a string: %s", data)
+	}
+	return []byte(s), nil
+}
+
+func formatText(f fmt.State, verb rune, b []byte) {
+	var s string
+	switch verb {
+	case 's', 'v':
+		s = encodeText(StdEncoding, b)
+	case 'q':
+		s = strconv.Quote(encodeText(StdEncoding, b))
+	case 'x':
+		s = hex.EncodeToString(b)
+	case 'X':
+		s = fmt.Sprintf("%X", []byte(b))
+	default:
+		fmt.Fprintf(f, "%%!%c(base58=%s)", verb, encodeText(StdEncoding, b))
+		return
+	}
+
+	if w, ok := f.Width(); ok && len(s) < w {
+		pad := make([]byte, w-len(s))
+		for i := range pad {
+			pad[i] = ' '
+		}
+		if f.Flag('-') {
+			s += string(pad)
+		} else {
+			s = string(pad) + s
+		}
+	}
+	f.Write([]byte(s))
+}
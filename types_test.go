@@ -0,0 +1,86 @@
+package base58
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestBytesJSON(t *testing.T) {
+	type record struct {
+		ID   Bytes  `json:"id"`
+		Key  Hash32 `json:"key"`
+		Addr Hash20 `json:"addr"`
+		Nil  Bytes  `json:"nil"`
+	}
+
+	id, _ := hex.DecodeString("0065a16059864a2fdbc7c99a4723a8395bc6f188eb")
+	have := record{ID: id}
+	copy(have.Key[:], bytes.Repeat([]byte{0xab}, 32))
+	copy(have.Addr[:], id[1:])
+
+	data, err := json.Marshal(have)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+
+	want := fmt.Sprintf(`{"id":%q,"key":%q,"addr":%q,"nil":null}`,
+		StdEncoding.EncodeToString(id), StdEncoding.EncodeToString(have.Key[:]), StdEncoding.EncodeToString(id[1:]))
+	if string(data) != want {
+		t.Errorf("want: %s have: %s", want, data)
+	}
+
+	var back record
+	if err := json.Unmarshal(data, &back); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if !bytes.Equal(back.ID, have.ID) || back.Key != have.Key || back.Addr != have.Addr || back.Nil != nil {
+		t.Errorf("want: %#v have: %#v", have, back)
+	}
+}
+
+func TestHashZeroValue(t *testing.T) {
+	var h Hash20
+	text, _ := h.MarshalText()
+	if want := "11111111111111111111"; string(text) != want {
+		t.Errorf("want: %q have: %q", want, text)
+	}
+
+	h[0] = 0xff
+	if err := h.UnmarshalText(text); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if h != (Hash20{}) {
+		t.Errorf("want zero value have: %x", h[:])
+	}
+}
+
+func TestHashWrongLength(t *testing.T) {
+	var h Hash32
+	if err := h.UnmarshalText([]byte("JxF12TrwXzT5jvT")); err == nil {
+		t.Errorf("want error for short input")
+	}
+	if err := json.Unmarshal([]byte(`12`), &h); err == nil {
+		t.Errorf("want error for non-string JSON")
+	}
+}
+
+func TestBytesFormat(t *testing.T) {
+	b := Bytes("Hello world")
+	for _, v := range []struct{ format, want string }{
+		{"%s", "JxF12TrwXzT5jvT"},
+		{"%v", "JxF12TrwXzT5jvT"},
+		{"%q", `"JxF12TrwXzT5jvT"`},
+		{"%x", "48656c6c6f20776f726c64"},
+		{"%X", "48656C6C6F20776F726C64"},
+		{"%17s|", "  JxF12TrwXzT5jvT|"},
+		{"%-17s|", "JxF12TrwXzT5jvT  |"},
+		{"%d", "%!d(base58=JxF12TrwXzT5jvT)"},
+	} {
+		if have := fmt.Sprintf(v.format, b); have != v.want {
+			t.Errorf("%s want: %q have: %q", v.format, v.want, have)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package base58
+
+import "sync"
+
+// validateStackLen is the longest string Validate checks using only
+// stack buffers
+const validateStackLen = 128
+
+// Validate checks that s decodes with enc, including the alphabet,
+// overflow, fixed width, checksum and version checks, and returns the
+// error Decode would. It doesn't allocate for strings up to 128
+// characters when enc has no checksum or a named checksum, like the
+// default sha256d.
+func (enc *Encoding) Validate(s string) error {
+	var srcBuf [validateStackLen]byte
+	var accBuf [(validateStackLen + 7) / 8]uint64
+
+	if enc.codec.TooLarge(len(s)) {
+		return ErrInputTooLarge
+	}
+
+	// the checksum function gets dst, so it comes from a pool rather than
+	// the stack
+	dstBuf := validateBufs.Get().(*[validateStackLen]byte)
+	defer validateBufs.Put(dstBuf)
+
+	src, dst, acc := srcBuf[:], dstBuf[:], accBuf[:]
+	if len(s) > validateStackLen {
+		src, dst, acc = make([]byte, len(s)), make([]byte, len(s)), make([]uint64, (len(s)+7)/8)
+	}
+	src = src[:copy(src, s)]
+
+	// every digit decodes to at most one byte, so dst can't be short
+	_, err := enc.codec.Decode(dst, src, acc)
+	return err
+}
+
+// validateBufs holds the decoding buffers of Validate
+var validateBufs = sync.Pool{New: func() interface{} { return new([validateStackLen]byte) }}
+
+// IsValid reports whether s decodes with enc, see Validate
+func (enc *Encoding) IsValid(s string) bool {
+	return enc.Validate(s) == nil
+}
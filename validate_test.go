@@ -0,0 +1,100 @@
+package base58
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	for _, pair := range base58BitcoinTestPairs {
+		_, want := BitcoinEncoding.DecodeString(pair.String)
+		if have := BitcoinEncoding.Validate(pair.String); have != want {
+			t.Errorf("%s want: %v have: %v", pair.String, want, have)
+		}
+	}
+
+	long := StdEncoding.EncodeToString([]byte(strings.Repeat("long input ", 20)))
+	for _, test := range []struct {
+		enc  *Encoding
+		s    string
+		want bool
+	}{
+		{BitcoinEncoding, "1AGNa15ZQXAZUgFiqJ2i7Z2DPU2J6hW62i", true},
+		{BitcoinEncoding, "1AGNa15ZQXAZUgFiqJ2i7Z2DPU2J6hW62j", false},
+		{BitcoinEncoding, "1Cwvi9VZSR3sXBS1pG59UowQRVc", false},
+		{StdEncoding, "12MBGHp6dGlRay3cazhw1mer7DLVJDHwG", false},
+		{StdEncoding, "\xff", false},
+		{StdEncoding, "", false},
+		{StdEncoding, long, true},
+		{StdEncoding, long + "0", false},
+	} {
+		if have := test.enc.IsValid(test.s); have != test.want {
+			t.Errorf("%.40s want: %t have: %t", test.s, test.want, have)
+		}
+	}
+
+	if have := StdEncoding.Validate("12l"); have != InvalidDigitError('l') {
+		t.Errorf("want: %v have: %v", InvalidDigitError('l'), have)
+	}
+}
+
+func TestValidateChecksums(t *testing.T) {
+	cb58, _ := Lookup("cb58")
+	versioned := BitcoinEncoding.With(WithVersion(0x05, 0x06), WithChecksum(3))
+	sha := BitcoinEncoding.With(withChecksumName("sha256"), WithMaxDecodedLen(8))
+	custom := BitcoinEncoding.With(WithChecksumFunc(sha256d))
+
+	for _, enc := range []*Encoding{cb58, versioned, sha, custom} {
+		var strs []string
+		for _, b := range [][]byte{{}, {0}, {5}, {5, 6, 7}, []byte("validate me"), []byte("0123456789")} {
+			strs = append(strs, enc.EncodeToString(b), StdEncoding.EncodeToString(b))
+		}
+		strs = append(strs, "1", "11", "2", "zzzzzzzzzzzzzzzzzzzz")
+
+		for _, s := range strs {
+			_, want := enc.DecodeString(s)
+			if have := enc.Validate(s); have != want {
+				t.Errorf("%v %s want: %v have: %v", enc, s, want, have)
+			}
+		}
+	}
+}
+
+func TestValidateAllocs(t *testing.T) {
+	for _, s := range []string{
+		"1AGNa15ZQXAZUgFiqJ2i7Z2DPU2J6hW62i",
+		"1AGNa15ZQXAZUgFiqJ2i7Z2DPU2J6hW62j",
+		"12MBGHp6dGlRay3cazhw1mer7DLVJDHwG",
+	} {
+		allocs := testing.AllocsPerRun(100, func() {
+			BitcoinEncoding.IsValid(s)
+			StdEncoding.IsValid(s)
+		})
+		if allocs != 0 {
+			t.Errorf("%s want: 0 allocs have: %v", s, allocs)
+		}
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	b.ReportAllocs()
+
+	initTestPairs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		BitcoinEncoding.Validate(testPairs[i].String)
+	}
+}
+
+func BenchmarkValidateDecodeString(b *testing.B) {
+	b.ReportAllocs()
+
+	initTestPairs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		_, err := BitcoinEncoding.DecodeString(testPairs[i].String)
+		_ = err == nil
+	}
+}